@@ -2,11 +2,16 @@ package manifest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -14,22 +19,197 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-func (l *list) Apply(ctx context.Context) error {
-	for _, v := range l.Resources() {
-		if err := l.apply(ctx, v); err != nil {
-			return err
+func (l *list) Apply(ctx context.Context, opts ...ApplyOptionFunc) ([]ApplyResult, error) {
+	options := newApplyOptions(opts...)
+	results := make([]ApplyResult, 0, l.Size())
+	resources := l.Resources()
+	pending := resources
+
+	var (
+		applied []*unstructured.Unstructured
+		errs    []error
+	)
+
+	if options.Prune && options.ApplySet == nil {
+		return nil, fmt.Errorf("pruning requires an ApplySet")
+	}
+
+	if set := options.ApplySet; set != nil {
+		if err := l.loadApplySet(ctx, set); err != nil {
+			return nil, err
+		}
+
+		resources = set.label(resources)
+		pending = resources
+
+		// the parent cannot be created before its namespace, so a namespace shipped with the bundle goes first
+		if i := parentNamespace(set, resources); i >= 0 {
+			result, err := l.apply(ctx, resources[i], options)
+			results = append(results, result)
+
+			if err != nil {
+				return results, err
+			}
+
+			applied = append(applied, resources[i])
+			pending = append(append([]*unstructured.Unstructured{}, resources[:i]...), resources[i+1:]...)
+		}
+
+		// the parent must list every group kind and namespace before any other member is applied
+		parent := set.parent()
+		if _, _, err := l.patch(ctx, parent, options); err != nil {
+			return results, l.newResourceError(parent, OperationApply, err)
+		}
+	}
+
+	for _, tier := range tiers(pending, options.Priorities) {
+		tierResults, err := l.applyTier(ctx, tier, options)
+		results = append(results, tierResults...)
+
+		if err != nil {
+			if !options.ContinueOnError {
+				return results, err
+			}
+
+			errs = append(errs, err)
+		}
+
+		succeeded := make([]*unstructured.Unstructured, 0, len(tier))
+
+		for i, v := range tierResults {
+			if v.Err == nil {
+				succeeded = append(succeeded, tier[i])
+			}
+		}
+
+		applied = append(applied, succeeded...)
+
+		if options.DryRun {
+			continue
+		}
+
+		if err := l.waitForEstablished(ctx, succeeded); err != nil {
+			if !options.ContinueOnError {
+				return results, err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	if options.Wait && !options.DryRun {
+		if err := l.waitForReady(ctx, applied); err != nil {
+			if !options.ContinueOnError {
+				return results, err
+			}
+
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	// leftovers are only pruned when everything else was applied
+	if err := errors.Join(errs...); err != nil {
+		return results, err
+	}
+
+	if set := options.ApplySet; set != nil && options.Prune {
+		pruned, err := l.prune(ctx, set, resources, options)
+		results = append(results, pruned...)
+
+		if err != nil {
+			return results, err
+		}
+
+		// only the group kinds and namespaces still in use are kept once the leftovers are gone
+		set.groupKinds.Clear()
+		set.namespaces.Clear()
+		set.record(resources)
+
+		parent := set.parent()
+		if _, _, err := l.patch(ctx, parent, options); err != nil {
+			return results, l.newResourceError(parent, OperationApply, err)
+		}
+	}
+
+	return results, nil
 }
 
-func (l *list) apply(ctx context.Context, obj *unstructured.Unstructured) error {
+// applyTier applies resources that do not depend on each other, using up to options.Concurrency workers.
+func (l *list) applyTier(ctx context.Context, tier []*unstructured.Unstructured, options *applyOptions) ([]ApplyResult, error) {
+	results := make([]ApplyResult, len(tier))
+	errs := make([]error, len(tier))
+
+	if options.Concurrency <= 1 {
+		for i, v := range tier {
+			results[i], errs[i] = l.apply(ctx, v, options)
+
+			if errs[i] != nil && !options.ContinueOnError {
+				return results[:i+1], errs[i]
+			}
+		}
+
+		return results, errors.Join(errs...)
+	}
+
+	workers := make(chan struct{}, options.Concurrency)
+
+	var (
+		wg      sync.WaitGroup
+		failed  atomic.Bool
+		started int
+	)
+
+	// like the sequential path, no more objects are applied once one failed, unless asked to go on
+	for ; started < len(tier) && !failed.Load(); started++ {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return results[:started], errors.Join(append(errs[:started], ctx.Err())...)
+		}
+
+		wg.Add(1)
+
+		go func(i int, obj *unstructured.Unstructured) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			results[i], errs[i] = l.apply(ctx, obj, options)
+			if errs[i] != nil && !options.ContinueOnError {
+				failed.Store(true)
+			}
+		}(started, tier[started])
+	}
+
+	wg.Wait()
+
+	return results[:started], errors.Join(errs[:started]...)
+}
+
+func (l *list) apply(ctx context.Context, obj *unstructured.Unstructured, options *applyOptions) (ApplyResult, error) {
 	log := logr.FromContextOrDiscard(ctx)
+	start := time.Now()
+
+	result := ApplyResult{
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		DryRun:           options.DryRun,
+	}
+
+	fail := func(err error) (ApplyResult, error) {
+		result.Action = ApplyActionFailed
+		result.Duration = time.Since(start)
+		result.Err = l.newResourceError(obj, OperationApply, err)
+
+		return result, result.Err
+	}
 
 	current, err := l.find(ctx, obj)
 	if err != nil {
-		return err
+		return fail(err)
 	}
 
 	gvk := obj.GroupVersionKind()
@@ -39,30 +219,36 @@ func (l *list) apply(ctx context.Context, obj *unstructured.Unstructured) error
 		kind = strings.ToLower(gvk.Kind)
 	}
 
-	if current == nil { // create
-		if _, err = l.patch(ctx, obj); err != nil {
-			return err
-		}
-
-		log.Info(fmt.Sprintf("%s %q created", kind, obj.GetName()))
-
-		return nil
+	suffix := ""
+	if options.DryRun {
+		suffix = " (server dry run)"
 	}
 
-	// update
-	updated, err := l.patch(ctx, obj)
+	updated, conflicts, err := l.patch(ctx, obj, options)
+	result.Conflicts = conflicts
+
 	if err != nil {
-		return err
+		return fail(err)
 	}
 
-	if current.GetResourceVersion() == updated.GetResourceVersion() {
-		log.Info(fmt.Sprintf("%s %q unchanged", kind, obj.GetName()))
-		return nil
+	result.NewResourceVersion = updated.GetResourceVersion()
+
+	switch {
+	case current == nil:
+		result.Action = ApplyActionCreated
+	case changed(current, updated, options.DryRun):
+		result.Action = ApplyActionConfigured
+		result.OldResourceVersion = current.GetResourceVersion()
+	default:
+		result.Action = ApplyActionUnchanged
+		result.OldResourceVersion = current.GetResourceVersion()
 	}
 
-	log.Info(fmt.Sprintf("%s %q configured", kind, obj.GetName()))
+	result.Duration = time.Since(start)
+
+	log.Info(fmt.Sprintf("%s %q %s%s", kind, obj.GetName(), result.Action, suffix))
 
-	return nil
+	return result, nil
 }
 
 func (l *list) find(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
@@ -84,7 +270,7 @@ func (l *list) find(ctx context.Context, obj *unstructured.Unstructured) (*unstr
 	}
 
 	result, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
-	if errors.IsNotFound(err) {
+	if apierrors.IsNotFound(err) {
 		return nil, nil
 	}
 
@@ -95,7 +281,9 @@ func (l *list) find(ctx context.Context, obj *unstructured.Unstructured) (*unstr
 	return result, nil
 }
 
-func (l *list) patch(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+// patch applies obj, resolving field manager conflicts with the configured policies when not forcing. The conflicts
+// met along the way are returned, whether they were resolved or not.
+func (l *list) patch(ctx context.Context, obj *unstructured.Unstructured, options *applyOptions) (*unstructured.Unstructured, []FieldConflict, error) {
 	gvk := obj.GroupVersionKind()
 	kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
 
@@ -105,12 +293,7 @@ func (l *list) patch(ctx context.Context, obj *unstructured.Unstructured) (*unst
 
 	mapper, err := l.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve REST mapping for %s: %w", kind, err)
-	}
-
-	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode JSON for %s: %w", kind, err)
+		return nil, nil, fmt.Errorf("failed to retrieve REST mapping for %s: %w", kind, err)
 	}
 
 	resource := l.client.Resource(mapper.Resource).Namespace(obj.GetNamespace())
@@ -118,13 +301,63 @@ func (l *list) patch(ctx context.Context, obj *unstructured.Unstructured) (*unst
 		resource = l.client.Resource(mapper.Resource)
 	}
 
-	force := true
-	options := metav1.PatchOptions{Force: &force, FieldManager: l.fieldManager}
+	send := func(obj *unstructured.Unstructured, force bool) (*unstructured.Unstructured, error) {
+		data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON for %s: %w", kind, err)
+		}
+
+		patchOptions := metav1.PatchOptions{Force: &force, FieldManager: l.fieldManager}
+
+		if options.DryRun {
+			patchOptions.DryRun = []string{metav1.DryRunAll}
+		}
+
+		patch, err := resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, patchOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to patch %s %q: %w", kind, obj.GetName(), err)
+		}
+
+		return patch, nil
+	}
+
+	patch, err := send(obj, options.Force)
+	if options.Force || !apierrors.IsConflict(err) {
+		return patch, nil, err
+	}
 
-	patch, err := resource.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, options)
+	resolved, force, conflicts, err := resolveConflicts(obj, err, options.ConflictPolicies)
 	if err != nil {
-		return nil, fmt.Errorf("failed to patch %s %q: %w", kind, obj.GetName(), err)
+		return nil, conflicts, err
+	}
+
+	patch, err = send(resolved, force)
+	if apierrors.IsConflict(err) {
+		// fields taken over by another manager in between are not resolved twice
+		_, _, again, err := resolveConflicts(resolved, err, nil)
+		conflicts = append(conflicts, again...)
+
+		var conflictErr *ConflictError
+		if !errors.As(err, &conflictErr) {
+			err = &ConflictError{Conflicts: conflicts, Err: err}
+		}
+
+		return nil, conflicts, err
 	}
 
-	return patch, nil
+	return patch, conflicts, err
+}
+
+// changed reports whether the patch modified the object. A dry run never bumps the resourceVersion, so the objects
+// themselves are compared, ignoring the managed fields.
+func changed(current, updated *unstructured.Unstructured, dryRun bool) bool {
+	if !dryRun {
+		return current.GetResourceVersion() != updated.GetResourceVersion()
+	}
+
+	current, updated = current.DeepCopy(), updated.DeepCopy()
+	current.SetManagedFields(nil)
+	updated.SetManagedFields(nil)
+
+	return !equality.Semantic.DeepEqual(current.Object, updated.Object)
 }
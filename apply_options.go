@@ -0,0 +1,84 @@
+package manifest
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+type applyOptions struct {
+	Wait             bool
+	DryRun           bool
+	ContinueOnError  bool
+	Prune            bool
+	ApplySet         *applySet
+	Force            bool
+	ConflictPolicies map[string]ConflictPolicy
+	Concurrency      int
+	Priorities       map[schema.GroupKind]int
+}
+
+type ApplyOptionFunc func(c *applyOptions)
+
+func newApplyOptions(opts ...ApplyOptionFunc) *applyOptions {
+	options := &applyOptions{Force: true, ConflictPolicies: map[string]ConflictPolicy{}, Priorities: newPriorities()}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+func KindPriorityForApply(gk schema.GroupKind, priority int) ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.Priorities[gk] = priority
+	}
+}
+
+func WaitForReady() ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.Wait = true
+	}
+}
+
+func DryRunForApply() ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.DryRun = true
+	}
+}
+
+// ApplySet makes the applied objects members of the ApplySet whose parent is the Secret name in namespace, which is
+// created or updated before any other member. The namespace must exist or be part of the List, in which case it is
+// applied first.
+func ApplySet(name, namespace string) ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.ApplySet = newApplySet(name, namespace)
+	}
+}
+
+func Prune() ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.Prune = true
+	}
+}
+
+func Concurrency(workers int) ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.Concurrency = workers
+	}
+}
+
+func ContinueOnErrorForApply() ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.ContinueOnError = true
+	}
+}
+
+func NoForceConflicts() ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.Force = false
+	}
+}
+
+func FieldConflictPolicy(path string, policy ConflictPolicy) ApplyOptionFunc {
+	return func(c *applyOptions) {
+		c.Force = false
+		c.ConflictPolicies[path] = policy
+	}
+}
@@ -0,0 +1,30 @@
+package manifest
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type ApplyAction string
+
+const (
+	ApplyActionCreated    ApplyAction = "created"
+	ApplyActionConfigured ApplyAction = "configured"
+	ApplyActionUnchanged  ApplyAction = "unchanged"
+	ApplyActionPruned     ApplyAction = "pruned"
+	ApplyActionFailed     ApplyAction = "failed"
+)
+
+type ApplyResult struct {
+	GroupVersionKind   schema.GroupVersionKind
+	Namespace          string
+	Name               string
+	Action             ApplyAction
+	OldResourceVersion string
+	NewResourceVersion string
+	DryRun             bool
+	Duration           time.Duration
+	Conflicts          []FieldConflict
+	Err                error
+}
@@ -0,0 +1,74 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestApplyTierConcurrentFailure(t *testing.T) {
+	const workers = 2
+
+	tier := make([]*unstructured.Unstructured, 10)
+	for i := range tier {
+		tier[i] = &unstructured.Unstructured{}
+		tier[i].SetAPIVersion("v1")
+		tier[i].SetKind("ConfigMap")
+		tier[i].SetNamespace("default")
+		tier[i].SetName(fmt.Sprintf("cm-%d", i))
+	}
+
+	tests := []struct {
+		name string
+		opts []ApplyOptionFunc
+		want func(patched int32) bool
+	}{
+		{
+			name: "stops starting workers",
+			opts: []ApplyOptionFunc{Concurrency(workers)},
+			// the workers in flight when the first one fails, plus the one already waiting for a slot
+			want: func(patched int32) bool { return patched <= workers+1 },
+		},
+		{
+			name: "continues on error",
+			opts: []ApplyOptionFunc{Concurrency(workers), ContinueOnErrorForApply()},
+			want: func(patched int32) bool { return patched == int32(len(tier)) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+			var patched atomic.Int32
+
+			client.PrependReactor("patch", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+				patched.Add(1)
+				return true, nil, errors.New("boom")
+			})
+
+			l := &list{client: client, mapper: mapper, fieldManager: "go-manifest"}
+
+			results, err := l.applyTier(context.Background(), tier, newApplyOptions(tt.opts...))
+			if err == nil {
+				t.Fatal("applyTier() error = nil, want the apply failures")
+			}
+
+			if n := patched.Load(); !tt.want(n) || len(results) != int(n) {
+				t.Errorf("applyTier() applied %d objects and returned %d results", n, len(results))
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Labels and annotations defined by the ApplySet specification (KEP-3659).
+const (
+	ApplySetPartOfLabel                    = "applyset.kubernetes.io/part-of"
+	ApplySetIDLabel                        = "applyset.kubernetes.io/id"
+	ApplySetToolingAnnotation              = "applyset.kubernetes.io/tooling"
+	ApplySetGroupKindsAnnotation           = "applyset.kubernetes.io/contains-group-kinds"
+	ApplySetAdditionalNamespacesAnnotation = "applyset.kubernetes.io/additional-namespaces"
+)
+
+const applySetTooling = "go-manifest/v1"
+
+// applySet tracks the members of an ApplySet whose parent is a Secret.
+type applySet struct {
+	name       string
+	namespace  string
+	groupKinds sets.Set[string]
+	namespaces sets.Set[string]
+}
+
+func newApplySet(name, namespace string) *applySet {
+	return &applySet{name: name, namespace: namespace, groupKinds: sets.New[string](), namespaces: sets.New[string]()}
+}
+
+func (s *applySet) id() string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s.%s.%s.%s", s.name, s.namespace, "Secret", "")))
+	return fmt.Sprintf("applyset-%s-v1", base64.RawURLEncoding.EncodeToString(hash[:]))
+}
+
+func (s *applySet) parent() *unstructured.Unstructured {
+	parent := &unstructured.Unstructured{}
+	parent.SetAPIVersion("v1")
+	parent.SetKind("Secret")
+	parent.SetName(s.name)
+	parent.SetNamespace(s.namespace)
+	parent.SetLabels(map[string]string{ApplySetIDLabel: s.id()})
+	parent.SetAnnotations(map[string]string{
+		ApplySetToolingAnnotation:              applySetTooling,
+		ApplySetGroupKindsAnnotation:           strings.Join(sets.List(s.groupKinds), ","),
+		ApplySetAdditionalNamespacesAnnotation: strings.Join(sets.List(s.namespaces.Difference(sets.New(s.namespace))), ","),
+	})
+
+	return parent
+}
+
+// label returns copies of the resources labeled as members of the ApplySet.
+func (s *applySet) label(resources []*unstructured.Unstructured) []*unstructured.Unstructured {
+	labeled := make([]*unstructured.Unstructured, 0, len(resources))
+
+	for _, v := range resources {
+		resource := v.DeepCopy()
+
+		labels := resource.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		labels[ApplySetPartOfLabel] = s.id()
+		resource.SetLabels(labels)
+
+		labeled = append(labeled, resource)
+	}
+
+	s.record(labeled)
+
+	return labeled
+}
+
+func (s *applySet) record(resources []*unstructured.Unstructured) {
+	for _, v := range resources {
+		s.groupKinds.Insert(v.GroupVersionKind().GroupKind().String())
+
+		if len(v.GetNamespace()) > 0 {
+			s.namespaces.Insert(v.GetNamespace())
+		}
+	}
+}
+
+// loadApplySet records the group kinds and namespaces listed by the live parent, if there is one.
+func (l *list) loadApplySet(ctx context.Context, s *applySet) error {
+	current, err := l.find(ctx, s.parent())
+	if err != nil || current == nil {
+		return err
+	}
+
+	if id := current.GetLabels()[ApplySetIDLabel]; id != s.id() {
+		return fmt.Errorf("secret %q is not the parent of ApplySet %q", s.name, s.id())
+	}
+
+	annotations := current.GetAnnotations()
+
+	if tooling := annotations[ApplySetToolingAnnotation]; len(tooling) > 0 && !strings.HasPrefix(tooling, "go-manifest/") {
+		return fmt.Errorf("ApplySet %q is managed by %q", s.id(), tooling)
+	}
+
+	for _, v := range strings.Split(annotations[ApplySetGroupKindsAnnotation], ",") {
+		if len(v) > 0 {
+			s.groupKinds.Insert(v)
+		}
+	}
+
+	for _, v := range strings.Split(annotations[ApplySetAdditionalNamespacesAnnotation], ",") {
+		if len(v) > 0 {
+			s.namespaces.Insert(v)
+		}
+	}
+
+	return nil
+}
+
+// prune deletes the live members of the ApplySet that are not among the resources being applied.
+func (l *list) prune(ctx context.Context, s *applySet, resources []*unstructured.Unstructured, options *applyOptions) ([]ApplyResult, error) {
+	keep := In(&list{resources: resources})
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", ApplySetPartOfLabel, s.id())}
+	namespaces := s.namespaces.Union(sets.New(s.namespace))
+
+	var candidates []*unstructured.Unstructured
+
+	for _, v := range sets.List(s.groupKinds) {
+		gk := schema.ParseGroupKind(v)
+
+		mapper, err := l.mapper.RESTMapping(gk)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve REST mapping for %s: %w", strings.ToLower(v), err)
+		}
+
+		var items []unstructured.Unstructured
+
+		if mapper.Scope.Name() == meta.RESTScopeNameRoot {
+			result, err := l.client.Resource(mapper.Resource).List(ctx, selector)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list %s: %w", strings.ToLower(v), err)
+			}
+
+			items = append(items, result.Items...)
+		} else {
+			for _, namespace := range sets.List(namespaces) {
+				result, err := l.client.Resource(mapper.Resource).Namespace(namespace).List(ctx, selector)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list %s in namespace %q: %w", strings.ToLower(v), namespace, err)
+				}
+
+				items = append(items, result.Items...)
+			}
+		}
+
+		for i := range items {
+			if !keep(&items[i]) {
+				candidates = append(candidates, &items[i])
+			}
+		}
+	}
+
+	deleteOptions := newDeleteOptions()
+	deleteOptions.DryRun = options.DryRun
+	results := make([]ApplyResult, 0, len(candidates))
+
+	var errs []error
+
+	for _, tier := range reverseTiers(tiers(candidates, options.Priorities)) {
+		for _, v := range tier {
+			start := time.Now()
+			result := ApplyResult{
+				GroupVersionKind:   v.GroupVersionKind(),
+				Namespace:          v.GetNamespace(),
+				Name:               v.GetName(),
+				Action:             ApplyActionPruned,
+				OldResourceVersion: v.GetResourceVersion(),
+				DryRun:             options.DryRun,
+			}
+
+			_, err := l.delete(ctx, v, nil, deleteOptions)
+			result.Duration = time.Since(start)
+
+			if err != nil {
+				result.Action = ApplyActionFailed
+				result.Err = l.newResourceError(v, OperationPrune, err)
+
+				if !options.ContinueOnError {
+					return append(results, result), result.Err
+				}
+
+				errs = append(errs, result.Err)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// parentNamespace returns the index of the namespace the parent of the ApplySet lives in among resources, or -1.
+func parentNamespace(s *applySet, resources []*unstructured.Unstructured) int {
+	for i, v := range resources {
+		if v.GroupVersionKind().GroupKind() == namespaceGroupKind && v.GetName() == s.namespace {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,134 @@
+package manifest
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func member(apiVersion, kind, namespace, name, id string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	if len(id) > 0 {
+		obj.SetLabels(map[string]string{ApplySetPartOfLabel: id})
+	}
+
+	return obj
+}
+
+func TestApplySetID(t *testing.T) {
+	// the ID kubectl computes for the Secret my-set in the namespace test:
+	// base64url(sha256("<name>.<namespace>.<kind>.<group>")) with the v1 prefix and suffix
+	const want = "applyset-0eFHV8ySqp7XoShsGvyWFQD3s96yqwHmzc4e0HR1dsY-v1"
+
+	if got := newApplySet("my-set", "test").id(); got != want {
+		t.Errorf("id() = %s, want %s", got, want)
+	}
+}
+
+func TestApplySetLabel(t *testing.T) {
+	s := newApplySet("my-set", "test")
+
+	resources := []*unstructured.Unstructured{
+		member("v1", "ConfigMap", "test", "a", ""),
+		member("apps/v1", "Deployment", "other", "b", ""),
+		member("rbac.authorization.k8s.io/v1", "ClusterRole", "", "c", ""),
+	}
+
+	labeled := s.label(resources)
+
+	for i, v := range labeled {
+		if id := v.GetLabels()[ApplySetPartOfLabel]; id != s.id() {
+			t.Errorf("label() %s part-of = %q, want %q", v.GetName(), id, s.id())
+		}
+
+		if len(resources[i].GetLabels()) > 0 {
+			t.Errorf("label() modified the original %s", resources[i].GetName())
+		}
+	}
+
+	parent := s.parent().GetAnnotations()
+
+	if got := parent[ApplySetGroupKindsAnnotation]; got != "ClusterRole.rbac.authorization.k8s.io,ConfigMap,Deployment.apps" {
+		t.Errorf("parent group kinds = %q", got)
+	}
+
+	if got := parent[ApplySetAdditionalNamespacesAnnotation]; got != "other" {
+		t.Errorf("parent additional namespaces = %q, want other", got)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := newApplySet("my-set", "test")
+	id := s.id()
+
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{configMaps: "ConfigMapList", deployments: "DeploymentList"},
+		member("v1", "ConfigMap", "test", "kept", id),
+		member("v1", "ConfigMap", "test", "stale", id),
+		member("v1", "ConfigMap", "test", "unrelated", "applyset-other-v1"),
+		member("apps/v1", "Deployment", "other", "stale", id),
+	)
+
+	// prune looks the group kinds up without a version, which the preferred versions answer
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{configMaps.GroupVersion(), deployments.GroupVersion()})
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+	l := &list{client: client, mapper: mapper}
+
+	// the live parent still lists the Deployment and its namespace from a previous apply
+	s.groupKinds.Insert("ConfigMap", "Deployment.apps")
+	s.namespaces.Insert("other")
+
+	results, err := l.prune(context.Background(), s, []*unstructured.Unstructured{member("v1", "ConfigMap", "test", "kept", id)}, newApplyOptions())
+	if err != nil {
+		t.Fatalf("prune() error = %v", err)
+	}
+
+	var pruned []string
+
+	for _, v := range results {
+		if v.Action != ApplyActionPruned {
+			t.Errorf("prune() %s %s/%s action = %s, want %s", v.GroupVersionKind.Kind, v.Namespace, v.Name, v.Action, ApplyActionPruned)
+		}
+
+		pruned = append(pruned, v.GroupVersionKind.Kind+"/"+v.Namespace+"/"+v.Name)
+	}
+
+	sort.Strings(pruned)
+
+	if want := []string{"ConfigMap/test/stale", "Deployment/other/stale"}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("prune() = %v, want %v", pruned, want)
+	}
+
+	remaining, err := client.Resource(configMaps).Namespace("test").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := sets.New[string]()
+	for _, v := range remaining.Items {
+		names.Insert(v.GetName())
+	}
+
+	if !names.Equal(sets.New("kept", "unrelated")) {
+		t.Errorf("configmaps left = %v, want kept and unrelated", sets.List(names))
+	}
+}
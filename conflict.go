@@ -0,0 +1,317 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+type ConflictPolicy string
+
+const (
+	ConflictPolicyForce ConflictPolicy = "Force"
+	ConflictPolicySkip  ConflictPolicy = "Skip"
+	ConflictPolicyFail  ConflictPolicy = "Fail"
+)
+
+// FieldConflict is a field of the applied configuration that is owned by another field manager.
+type FieldConflict struct {
+	Field   string
+	Manager string
+	Message string
+	Policy  ConflictPolicy
+}
+
+// ConflictError is returned when Apply finds conflicts that the configured policies do not allow to resolve.
+type ConflictError struct {
+	Conflicts []FieldConflict
+	Err       error
+}
+
+func (e *ConflictError) Error() string {
+	unresolved := make([]string, 0, len(e.Conflicts))
+
+	for _, v := range e.Conflicts {
+		if v.Policy == ConflictPolicyFail {
+			unresolved = append(unresolved, fmt.Sprintf("%s (managed by %q)", v.Field, v.Manager))
+		}
+	}
+
+	return fmt.Sprintf("%v: unresolved conflicts: %s", e.Err, strings.Join(unresolved, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+var conflictManagerRegexp = regexp.MustCompile(`conflict with "([^"]*)"`)
+
+// resolveConflicts applies the conflict policies to the conflicts reported by err. It returns the object to apply
+// again, without the skipped fields, and whether the remaining conflicts must be forced.
+func resolveConflicts(obj *unstructured.Unstructured, err error, policies map[string]ConflictPolicy) (*unstructured.Unstructured, bool, []FieldConflict, error) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil, false, nil, err
+	}
+
+	resolved := obj.DeepCopy()
+	conflicts := make([]FieldConflict, 0, len(status.Status().Details.Causes))
+	force, failed := false, false
+
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+
+		conflict := FieldConflict{Field: cause.Field, Message: cause.Message, Policy: conflictPolicy(cause.Field, policies)}
+
+		if match := conflictManagerRegexp.FindStringSubmatch(cause.Message); match != nil {
+			conflict.Manager = match[1]
+		}
+
+		switch conflict.Policy {
+		case ConflictPolicyForce:
+			force = true
+		case ConflictPolicySkip:
+			if object, ok := removeFieldPath(resolved.Object, cause.Field); ok {
+				resolved.Object = object.(map[string]interface{})
+			} else {
+				conflict.Policy = ConflictPolicyFail
+			}
+		}
+
+		failed = failed || conflict.Policy == ConflictPolicyFail
+		conflicts = append(conflicts, conflict)
+	}
+
+	if len(conflicts) == 0 {
+		return nil, false, nil, err
+	}
+
+	if failed {
+		return nil, false, conflicts, &ConflictError{Conflicts: conflicts, Err: err}
+	}
+
+	return resolved, force, conflicts, nil
+}
+
+// conflictPolicy returns the policy of the longest path that contains the field.
+func conflictPolicy(field string, policies map[string]ConflictPolicy) ConflictPolicy {
+	policy, length := ConflictPolicyFail, -1
+
+	for path, v := range policies {
+		if len(path) <= length {
+			continue
+		}
+
+		if field == path || strings.HasPrefix(field, path+".") || strings.HasPrefix(field, path+"[") {
+			policy, length = v, len(path)
+		}
+	}
+
+	return policy
+}
+
+// removeFieldPath removes the field identified by a managed fields path, such as
+// .spec.template.spec.containers[name="nginx"].image, returning the updated node.
+func removeFieldPath(node interface{}, path string) (interface{}, bool) {
+	switch {
+	case strings.HasPrefix(path, "."):
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return node, false
+		}
+
+		key, ok := matchKey(m, path[1:])
+		if !ok {
+			return node, false
+		}
+
+		rest := path[1+len(key):]
+		if len(rest) == 0 {
+			delete(m, key)
+			return m, true
+		}
+
+		child, ok := removeFieldPath(m[key], rest)
+		if ok {
+			m[key] = child
+		}
+
+		return m, ok
+	case strings.HasPrefix(path, "["):
+		items, ok := node.([]interface{})
+		if !ok {
+			return node, false
+		}
+
+		end := closingBracket(path)
+		if end < 0 {
+			return node, false
+		}
+
+		i := findItem(items, path[1:end])
+		if i < 0 {
+			return node, false
+		}
+
+		rest := path[end+1:]
+		if len(rest) == 0 {
+			return append(items[:i:i], items[i+1:]...), true
+		}
+
+		child, ok := removeFieldPath(items[i], rest)
+		if ok {
+			items[i] = child
+		}
+
+		return items, ok
+	}
+
+	return node, false
+}
+
+// matchKey finds the longest key of m the path starts with. Keys are not escaped in managed fields paths, so keys
+// containing dots, like most labels, are only told apart by looking at the object.
+func matchKey(m map[string]interface{}, path string) (string, bool) {
+	key, found := "", false
+
+	for k := range m {
+		if found && len(k) <= len(key) {
+			continue
+		}
+
+		if path == k || strings.HasPrefix(path, k+".") || strings.HasPrefix(path, k+"[") {
+			key, found = k, true
+		}
+	}
+
+	return key, found
+}
+
+func closingBracket(path string) int {
+	quoted, escaped := false, false
+
+	for i, c := range path {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = quoted
+		case c == '"':
+			quoted = !quoted
+		case c == ']' && !quoted:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// findItem returns the index of the list item matching the selector, which is either an index, a value (=value) or
+// a set of keys (name="nginx",protocol="TCP").
+func findItem(items []interface{}, selector string) int {
+	if i, err := strconv.Atoi(selector); err == nil {
+		if i < 0 || i >= len(items) {
+			return -1
+		}
+
+		return i
+	}
+
+	if strings.HasPrefix(selector, "=") {
+		for i, v := range items {
+			if sameJSONValue(v, selector[1:]) {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	keys := splitKeys(selector)
+
+	for i, v := range items {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		matched := true
+
+		for key, value := range keys {
+			if !sameJSONValue(item[key], value) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func splitKeys(selector string) map[string]string {
+	keys := make(map[string]string)
+	quoted, escaped, start := false, false, 0
+
+	for i := 0; i <= len(selector); i++ {
+		if i < len(selector) {
+			c := selector[i]
+
+			switch {
+			case escaped:
+				escaped = false
+				continue
+			case c == '\\':
+				escaped = quoted
+				continue
+			case c == '"':
+				quoted = !quoted
+				continue
+			case c != ',' || quoted:
+				continue
+			}
+		}
+
+		if key, value, ok := strings.Cut(selector[start:i], "="); ok {
+			keys[key] = value
+		}
+
+		start = i + 1
+	}
+
+	return keys
+}
+
+// sameJSONValue compares v with a JSON encoded value. Both sides go through encoding/json, so numbers compare equal
+// whether they were decoded as int64 or float64.
+func sameJSONValue(v interface{}, data string) bool {
+	var expected interface{}
+	if err := json.Unmarshal([]byte(data), &expected); err != nil {
+		return false
+	}
+
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	var actual interface{}
+	if err := json.Unmarshal(encoded, &actual); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(actual, expected)
+}
@@ -0,0 +1,274 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestRemoveFieldPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  string
+		path    string
+		want    string
+		removed bool
+	}{
+		{
+			name:    "nested field",
+			object:  `{"spec":{"replicas":3,"paused":true}}`,
+			path:    ".spec.replicas",
+			want:    `{"spec":{"paused":true}}`,
+			removed: true,
+		},
+		{
+			name:    "key containing dots",
+			object:  `{"metadata":{"labels":{"app.kubernetes.io/name":"nginx","app":"nginx"}}}`,
+			path:    ".metadata.labels.app.kubernetes.io/name",
+			want:    `{"metadata":{"labels":{"app":"nginx"}}}`,
+			removed: true,
+		},
+		{
+			name:    "item by quoted key",
+			object:  `{"containers":[{"name":"nginx","image":"nginx:1"},{"name":"sidecar","image":"busybox"}]}`,
+			path:    `.containers[name="nginx"].image`,
+			want:    `{"containers":[{"name":"nginx"},{"name":"sidecar","image":"busybox"}]}`,
+			removed: true,
+		},
+		{
+			name:    "item by several keys",
+			object:  `{"ports":[{"containerPort":80,"protocol":"TCP"},{"containerPort":80,"protocol":"UDP"}]}`,
+			path:    `.ports[containerPort=80,protocol="UDP"]`,
+			want:    `{"ports":[{"containerPort":80,"protocol":"TCP"}]}`,
+			removed: true,
+		},
+		{
+			name:    "key with escaped quote and bracket",
+			object:  `{"items":[{"name":"a\"]b","value":"x"},{"name":"c","value":"y"}]}`,
+			path:    `.items[name="a\"]b"].value`,
+			want:    `{"items":[{"name":"a\"]b"},{"name":"c","value":"y"}]}`,
+			removed: true,
+		},
+		{
+			name:    "set item by value",
+			object:  `{"finalizers":["a","b"]}`,
+			path:    `.finalizers[="b"]`,
+			want:    `{"finalizers":["a"]}`,
+			removed: true,
+		},
+		{
+			name:    "set item by large number",
+			object:  `{"values":[1000000,2]}`,
+			path:    `.values[=1e+06]`,
+			want:    `{"values":[2]}`,
+			removed: true,
+		},
+		{
+			name:    "numeric index",
+			object:  `{"args":["a","b","c"]}`,
+			path:    ".args[1]",
+			want:    `{"args":["a","c"]}`,
+			removed: true,
+		},
+		{
+			name:   "index out of range",
+			object: `{"args":["a"]}`,
+			path:   ".args[-1]",
+			want:   `{"args":["a"]}`,
+		},
+		{
+			name:   "missing item",
+			object: `{"containers":[{"name":"nginx"}]}`,
+			path:   `.containers[name="other"].image`,
+			want:   `{"containers":[{"name":"nginx"}]}`,
+		},
+		{
+			name:   "missing field",
+			object: `{"spec":{}}`,
+			path:   ".spec.replicas",
+			want:   `{"spec":{}}`,
+		},
+		{
+			name:   "unterminated selector",
+			object: `{"containers":[{"name":"nginx"}]}`,
+			path:   `.containers[name="nginx`,
+			want:   `{"containers":[{"name":"nginx"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var object, want interface{}
+
+			if err := json.Unmarshal([]byte(tt.object), &object); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+
+			got, removed := removeFieldPath(object, tt.path)
+			if removed != tt.removed {
+				t.Errorf("removeFieldPath() removed = %v, want %v", removed, tt.removed)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("removeFieldPath() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSplitKeys(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     map[string]string
+	}{
+		{selector: `name="nginx"`, want: map[string]string{"name": `"nginx"`}},
+		{selector: `containerPort=80,protocol="TCP"`, want: map[string]string{"containerPort": "80", "protocol": `"TCP"`}},
+		{selector: `name="a,b"`, want: map[string]string{"name": `"a,b"`}},
+		{selector: `name="a\",b"`, want: map[string]string{"name": `"a\",b"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			if got := splitKeys(tt.selector); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameJSONValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		data  string
+		want  bool
+	}{
+		{name: "string", value: "nginx", data: `"nginx"`, want: true},
+		{name: "unquoted string", value: "nginx", data: `nginx`, want: false},
+		{name: "int and float", value: int64(1000000), data: `1e+06`, want: true},
+		{name: "float and int", value: float64(80), data: `80`, want: true},
+		{name: "different numbers", value: int64(80), data: `81`, want: false},
+		{name: "number and string", value: int64(80), data: `"80"`, want: false},
+		{name: "object", value: map[string]interface{}{"a": int64(1)}, data: `{"a":1}`, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameJSONValue(tt.value, tt.data); got != tt.want {
+				t.Errorf("sameJSONValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func conflictStatus(fields ...string) *apierrors.StatusError {
+	causes := make([]metav1.StatusCause, 0, len(fields))
+	for _, field := range fields {
+		causes = append(causes, metav1.StatusCause{
+			Type:    metav1.CauseTypeFieldManagerConflict,
+			Message: `conflict with "kubectl-edit" using apps/v1`,
+			Field:   field,
+		})
+	}
+
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusConflict,
+		Reason:  metav1.StatusReasonConflict,
+		Details: &metav1.StatusDetails{Causes: causes},
+		Message: "Apply failed with conflicts",
+	}}
+}
+
+func TestPatchConflicts(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec":       map[string]interface{}{"replicas": int64(3), "paused": false},
+	}}
+
+	tests := []struct {
+		name      string
+		responses []error
+		policies  []ApplyOptionFunc
+		want      []FieldConflict
+		wantErr   bool
+	}{
+		{
+			name:      "resolved by force and skip",
+			responses: []error{conflictStatus(".spec.replicas", ".spec.paused"), nil},
+			policies:  []ApplyOptionFunc{FieldConflictPolicy(".spec.replicas", ConflictPolicySkip), FieldConflictPolicy(".spec.paused", ConflictPolicyForce)},
+			want: []FieldConflict{
+				{Field: ".spec.replicas", Manager: "kubectl-edit", Policy: ConflictPolicySkip},
+				{Field: ".spec.paused", Manager: "kubectl-edit", Policy: ConflictPolicyForce},
+			},
+		},
+		{
+			name:      "unresolved",
+			responses: []error{conflictStatus(".spec.replicas")},
+			want:      []FieldConflict{{Field: ".spec.replicas", Manager: "kubectl-edit", Policy: ConflictPolicyFail}},
+			wantErr:   true,
+		},
+		{
+			name:      "conflict on retry",
+			responses: []error{conflictStatus(".spec.replicas"), conflictStatus(".spec.paused")},
+			policies:  []ApplyOptionFunc{FieldConflictPolicy(".spec.replicas", ConflictPolicySkip)},
+			want: []FieldConflict{
+				{Field: ".spec.replicas", Manager: "kubectl-edit", Policy: ConflictPolicySkip},
+				{Field: ".spec.paused", Manager: "kubectl-edit", Policy: ConflictPolicyFail},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(deployment.GroupVersionKind(), meta.RESTScopeNamespace)
+
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			responses := tt.responses
+
+			client.PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				err := responses[0]
+				responses = responses[1:]
+
+				return true, deployment.DeepCopy(), err
+			})
+
+			l := &list{client: client, mapper: mapper, fieldManager: "go-manifest"}
+			options := newApplyOptions(append([]ApplyOptionFunc{NoForceConflicts()}, tt.policies...)...)
+
+			_, conflicts, err := l.patch(context.Background(), deployment, options)
+
+			var conflictErr *ConflictError
+			if tt.wantErr != errors.As(err, &conflictErr) {
+				t.Fatalf("patch() error = %v, want a ConflictError: %v", err, tt.wantErr)
+			}
+
+			for i := range conflicts {
+				conflicts[i].Message = ""
+			}
+
+			if !reflect.DeepEqual(conflicts, tt.want) {
+				t.Errorf("patch() conflicts = %+v, want %+v", conflicts, tt.want)
+			}
+		})
+	}
+}
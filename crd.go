@@ -0,0 +1,64 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+type groupInvalidator interface {
+	invalidateGroup(groupName string)
+}
+
+func (l *list) waitForEstablished(ctx context.Context, resources []*unstructured.Unstructured) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	for _, obj := range resources {
+		gvk := obj.GroupVersionKind()
+		if gvk.GroupKind() != crdGroupKind {
+			continue
+		}
+
+		mapper, err := l.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			err = fmt.Errorf("failed to retrieve REST mapping for customresourcedefinition.apiextensions.k8s.io: %w", err)
+			return l.newResourceError(obj, OperationWait, err)
+		}
+
+		resource := l.client.Resource(mapper.Resource)
+
+		err = wait.ExponentialBackoffWithContext(ctx, defaultBackoff, func(ctx context.Context) (done bool, err error) {
+			current, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+
+			return isEstablished(current), nil
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to wait for customresourcedefinition.apiextensions.k8s.io %q to be established: %w", obj.GetName(), err)
+			return l.newResourceError(obj, OperationWait, err)
+		}
+
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		if m, ok := l.mapper.(groupInvalidator); ok {
+			m.invalidateGroup(group)
+		}
+
+		log.Info(fmt.Sprintf("customresourcedefinition.apiextensions.k8s.io %q established", obj.GetName()))
+	}
+
+	return nil
+}
+
+func isEstablished(crd *unstructured.Unstructured) bool {
+	condition := findCondition(crd, "Established")
+	return condition != nil && condition["status"] == string(metav1.ConditionTrue)
+}
@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestWaitForEstablishedResourceError(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("widgets.example.com")
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(crdGroupKind.WithVersion("v1"), meta.RESTScopeRoot)
+
+	origin := Origin{Source: "crds.yaml", Line: 1}
+	l := &list{
+		resources: []*unstructured.Unstructured{crd},
+		origins:   map[string]Origin{resourceKey(crd): origin},
+		client:    dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		mapper:    mapper,
+	}
+
+	err := l.waitForEstablished(context.Background(), l.resources)
+
+	var resourceErr *ResourceError
+	if !errors.As(err, &resourceErr) {
+		t.Fatalf("waitForEstablished() error = %v, want a *ResourceError", err)
+	}
+
+	if resourceErr.Operation != OperationWait || resourceErr.Origin == nil || *resourceErr.Origin != origin {
+		t.Errorf("waitForEstablished() error operation = %s, origin = %v, want %s at %v", resourceErr.Operation, resourceErr.Origin, OperationWait, origin)
+	}
+}
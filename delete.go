@@ -2,30 +2,97 @@ package manifest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/api/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-func (l *list) Delete(ctx context.Context, opts ...DeleteOptionFunc) error {
+func (l *list) Delete(ctx context.Context, opts ...DeleteOptionFunc) ([]DeleteResult, error) {
 	options := newDeleteOptions(opts...)
 
-	for _, v := range l.Resources() {
-		if err := l.delete(ctx, v, options); err != nil {
-			return err
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	var (
+		results []DeleteResult
+		errs    []error
+	)
+
+	for _, tier := range reverseTiers(tiers(l.Resources(), options.Priorities)) {
+		deleted := make([]*unstructured.Unstructured, 0, len(tier))
+
+		for _, v := range tier {
+			result := DeleteResult{
+				GroupVersionKind: v.GroupVersionKind(),
+				Namespace:        v.GetNamespace(),
+				Name:             v.GetName(),
+				Action:           DeleteActionDeleted,
+				DryRun:           options.DryRun,
+			}
+
+			current, ok, reason, err := l.deletable(ctx, v, options)
+			if err == nil && ok {
+				if ok, err = l.delete(ctx, v, current, options); !ok {
+					reason = "not found"
+				}
+			}
+
+			if err != nil {
+				result.Action = DeleteActionFailed
+				result.Err = l.newResourceError(v, OperationDelete, err)
+				results = append(results, result)
+
+				if !options.ContinueOnError {
+					return results, result.Err
+				}
+
+				errs = append(errs, result.Err)
+
+				continue
+			}
+
+			if !ok {
+				result.Action = DeleteActionSkipped
+				result.Reason = reason
+				results = append(results, result)
+
+				continue
+			}
+
+			results = append(results, result)
+			deleted = append(deleted, v)
+		}
+
+		if !options.Wait || options.DryRun {
+			continue
+		}
+
+		if err := l.waitForDeletion(ctx, deleted, options); err != nil {
+			if !options.ContinueOnError {
+				return results, err
+			}
+
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return results, errors.Join(errs...)
 }
 
-func (l *list) delete(ctx context.Context, obj *unstructured.Unstructured, options *deleteOptions) error {
+// delete deletes obj by name and reports whether it was found. When current, the live object an ownership check was made
+// on, is given, the delete only goes through if the object has not been replaced or changed since.
+func (l *list) delete(ctx context.Context, obj, current *unstructured.Unstructured, options *deleteOptions) (bool, error) {
 	log := logr.FromContextOrDiscard(ctx)
 
 	gvk := obj.GroupVersionKind()
@@ -37,7 +104,7 @@ func (l *list) delete(ctx context.Context, obj *unstructured.Unstructured, optio
 
 	mapper, err := l.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve REST mapping for %s: %w", kind, err)
+		return false, fmt.Errorf("failed to retrieve REST mapping for %s: %w", kind, err)
 	}
 
 	resource := l.client.Resource(mapper.Resource).Namespace(obj.GetNamespace())
@@ -45,29 +112,121 @@ func (l *list) delete(ctx context.Context, obj *unstructured.Unstructured, optio
 		resource = l.client.Resource(mapper.Resource)
 	}
 
-	err = resource.Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
-	if errors.IsNotFound(err) {
-		return nil
+	deleteOptions, err := options.toDeleteOptions(obj, current)
+	if err != nil {
+		return false, err
+	}
+
+	err = resource.Delete(ctx, obj.GetName(), deleteOptions)
+	if apierrors.IsNotFound(err) {
+		return false, nil
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to delete %s %q: %w", kind, obj.GetName(), err)
+		return false, fmt.Errorf("failed to delete %s %q: %w", kind, obj.GetName(), err)
+	}
+
+	if options.DryRun {
+		log.Info(fmt.Sprintf("%s %q deleted (server dry run)", kind, obj.GetName()))
+		return true, nil
 	}
 
 	log.Info(fmt.Sprintf("%s %q deleted", kind, obj.GetName()))
 
-	if !options.Wait {
-		return nil
+	return true, nil
+}
+
+func (l *list) waitForDeletion(ctx context.Context, resources []*unstructured.Unstructured, options *deleteOptions) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	describe := func(obj *unstructured.Unstructured) string {
+		gvk := obj.GroupVersionKind()
+		kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+		if len(gvk.Group) == 0 {
+			kind = strings.ToLower(gvk.Kind)
+		}
+
+		return fmt.Sprintf("%s %q", kind, obj.GetName())
 	}
 
-	return wait.ExponentialBackoff(defaultBackoff, func() (done bool, err error) {
-		_, err = resource.Get(ctx, obj.GetName(), metav1.GetOptions{})
-		if errors.IsNotFound(err) {
-			return true, nil
-		} else if err != nil {
-			return false, err
+	pending := resources
+	terminating := make(map[*unstructured.Unstructured]*unstructured.Unstructured, len(resources))
+	finalizers := make(map[*unstructured.Unstructured]finalizersSnapshot, len(resources))
+
+	var stuck []*unstructured.Unstructured
+
+	err := wait.ExponentialBackoffWithContext(ctx, options.Backoff, func(ctx context.Context) (done bool, err error) {
+		remaining := make([]*unstructured.Unstructured, 0, len(pending))
+
+		for _, obj := range pending {
+			current, err := l.find(ctx, obj)
+			if err != nil {
+				return false, err
+			}
+
+			if current == nil {
+				continue
+			}
+
+			terminating[obj] = current
+
+			if options.StuckFinalizerTimeout > 0 && current.GetDeletionTimestamp() != nil {
+				snapshot, ok := finalizers[obj]
+				if !ok || !snapshot.equal(finalizersOf(current)) {
+					snapshot = finalizersSnapshot{finalizers: finalizersOf(current), since: time.Now()}
+					finalizers[obj] = snapshot
+				}
+
+				if len(snapshot.finalizers) > 0 && time.Since(snapshot.since) >= options.StuckFinalizerTimeout {
+					if !options.RemoveStuckFinalizers {
+						log.Info(fmt.Sprintf("%s is stuck on finalizers: %s", describe(obj), strings.Join(snapshot.finalizers, ", ")))
+						stuck = append(stuck, obj)
+
+						continue
+					}
+
+					if err := l.removeFinalizers(ctx, current); err != nil {
+						return false, err
+					}
+
+					delete(finalizers, obj)
+				}
+			}
+
+			remaining = append(remaining, obj)
 		}
 
-		return false, nil
+		pending = remaining
+
+		return len(pending) == 0, nil
 	})
+	if err != nil && !wait.Interrupted(err) {
+		return err
+	}
+
+	errs := make([]error, 0, len(stuck)+len(pending))
+
+	for _, obj := range stuck {
+		err := fmt.Errorf("%s is stuck on finalizers: %s", describe(obj), strings.Join(finalizers[obj].finalizers, ", "))
+		errs = append(errs, l.newResourceError(obj, OperationWait, err))
+	}
+
+	if err != nil {
+		for _, obj := range pending {
+			err := fmt.Errorf("%s is still terminating", describe(obj))
+			// objects not polled before the wait was interrupted have no known state
+			if current, ok := terminating[obj]; ok && len(finalizersOf(current)) > 0 {
+				err = fmt.Errorf("%s is still terminating (finalizers: %s)", describe(obj), strings.Join(finalizersOf(current), ", "))
+			}
+
+			errs = append(errs, l.newResourceError(obj, OperationWait, err))
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("failed to wait for resources to be deleted: %w", errors.Join(errs...))
 }
@@ -0,0 +1,165 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+// DeleteCollection deletes, for every kind in the List, the objects matching the selector in the namespaces the List
+// uses. OnlyOwnedByApplySet narrows the selector to the ApplySet members, while options checked per object, OnlyOwned,
+// the preconditions and the stuck finalizer ones, are refused.
+func (l *list) DeleteCollection(ctx context.Context, selector labels.Selector, opts ...DeleteOptionFunc) error {
+	options := newDeleteOptions(opts...)
+
+	if selector == nil || selector.Empty() {
+		return fmt.Errorf("refusing to delete collections without a label selector")
+	}
+
+	// ownership by field manager and finalizers are checked per object, which a collection delete cannot do
+	if options.OwnedByFieldManager {
+		return fmt.Errorf("OnlyOwned is not supported when deleting collections")
+	}
+
+	if options.PreconditionUID || options.PreconditionResourceVersion {
+		return fmt.Errorf("preconditions are not supported when deleting collections")
+	}
+
+	if options.StuckFinalizerTimeout > 0 {
+		return fmt.Errorf("DetectStuckFinalizers and RemoveStuckFinalizers are not supported when deleting collections")
+	}
+
+	if options.OwnedByApplySets.Len() > 0 {
+		requirement, err := labels.NewRequirement(ApplySetPartOfLabel, selection.In, sets.List(options.OwnedByApplySets))
+		if err != nil {
+			return fmt.Errorf("failed to select ApplySet members: %w", err)
+		}
+
+		selector = selector.Add(*requirement)
+	}
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	var errs []error
+
+	for _, tier := range reverseTiers(tiers(l.Resources(), options.Priorities)) {
+		seen := make(map[schema.GroupVersionKind]bool)
+
+		for _, v := range tier {
+			gvk := v.GroupVersionKind()
+			if seen[gvk] {
+				continue
+			}
+
+			seen[gvk] = true
+
+			if err := l.deleteCollection(ctx, v, selector, options); err != nil {
+				if !options.ContinueOnError {
+					return err
+				}
+
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deleteCollection deletes the objects matching the selector that have the same kind as obj, in every namespace where
+// the List has objects of that kind.
+func (l *list) deleteCollection(ctx context.Context, obj *unstructured.Unstructured, selector labels.Selector, options *deleteOptions) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	gvk := obj.GroupVersionKind()
+	kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+	if len(gvk.Group) == 0 {
+		kind = strings.ToLower(gvk.Kind)
+	}
+
+	mapper, err := l.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve REST mapping for %s: %w", kind, err)
+	}
+
+	resources := map[string]dynamic.ResourceInterface{"": l.client.Resource(mapper.Resource)}
+
+	if mapper.Scope.Name() != meta.RESTScopeNameRoot {
+		resources = make(map[string]dynamic.ResourceInterface)
+
+		for _, v := range l.Resources() {
+			if v.GroupVersionKind() != gvk {
+				continue
+			}
+
+			// an empty namespace would delete the collection in every namespace
+			if len(v.GetNamespace()) == 0 {
+				log.Info(fmt.Sprintf("%s %q skipped: no namespace", kind, v.GetName()))
+				continue
+			}
+
+			resources[v.GetNamespace()] = l.client.Resource(mapper.Resource).Namespace(v.GetNamespace())
+		}
+	}
+
+	deleteOptions, err := options.toDeleteOptions(obj, nil)
+	if err != nil {
+		return err
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: selector.String()}
+
+	for _, namespace := range sets.List(sets.KeySet(resources)) {
+		scope := fmt.Sprintf("in namespace %q", namespace)
+		if mapper.Scope.Name() == meta.RESTScopeNameRoot {
+			scope = "cluster-wide"
+		}
+
+		if err = resources[namespace].DeleteCollection(ctx, deleteOptions, listOptions); err != nil {
+			return fmt.Errorf("failed to delete %s %s matching %q: %w", kind, scope, selector, err)
+		}
+
+		if options.DryRun {
+			log.Info(fmt.Sprintf("%s %s matching %q deleted (server dry run)", kind, scope, selector))
+			continue
+		}
+
+		log.Info(fmt.Sprintf("%s %s matching %q deleted", kind, scope, selector))
+
+		if !options.Wait {
+			continue
+		}
+
+		err = wait.ExponentialBackoffWithContext(ctx, options.Backoff, func(ctx context.Context) (done bool, err error) {
+			result, err := resources[namespace].List(ctx, listOptions)
+			if err != nil {
+				return false, err
+			}
+
+			return len(result.Items) == 0, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to wait for %s %s matching %q to be deleted: %w", kind, scope, selector, err)
+		}
+	}
+
+	return nil
+}
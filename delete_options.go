@@ -1,13 +1,45 @@
 package manifest
 
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
 type deleteOptions struct {
-	Wait bool
+	Wait            bool
+	Backoff         wait.Backoff
+	Timeout         time.Duration
+	DryRun          bool
+	ContinueOnError bool
+	Priorities      map[schema.GroupKind]int
+
+	StuckFinalizerTimeout time.Duration
+	RemoveStuckFinalizers bool
+
+	OwnedByFieldManager bool
+	OwnedByApplySets    sets.Set[string]
+
+	PropagationPolicies         []propagationPolicy
+	GracePeriodSeconds          *int64
+	PreconditionUID             bool
+	PreconditionResourceVersion bool
+}
+
+type propagationPolicy struct {
+	filter Filter
+	policy metav1.DeletionPropagation
 }
 
 type DeleteOptionFunc func(c *deleteOptions)
 
 func newDeleteOptions(opts ...DeleteOptionFunc) *deleteOptions {
-	options := &deleteOptions{}
+	options := &deleteOptions{Backoff: defaultBackoff, Priorities: newPriorities(), OwnedByApplySets: sets.New[string]()}
 	for _, opt := range opts {
 		opt(options)
 	}
@@ -15,8 +47,181 @@ func newDeleteOptions(opts ...DeleteOptionFunc) *deleteOptions {
 	return options
 }
 
+// checksOwnership reports whether objects are only deleted when owned by the field manager or an ApplySet.
+func (o *deleteOptions) checksOwnership() bool {
+	return o.OwnedByFieldManager || len(o.OwnedByApplySets) > 0
+}
+
+// toDeleteOptions builds the options to delete obj with. Preconditions take the uid or resourceVersion of obj when it
+// was read back from the cluster, and those of current, the live object, otherwise; asking for one neither has is an
+// error rather than a no-op. When the ownership of current was checked, the delete is made conditional on it so an
+// object replaced in between is not deleted.
+func (o *deleteOptions) toDeleteOptions(obj, current *unstructured.Unstructured) (metav1.DeleteOptions, error) {
+	options := metav1.DeleteOptions{GracePeriodSeconds: o.GracePeriodSeconds}
+
+	if o.DryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	// the last matching policy wins
+	for i := len(o.PropagationPolicies) - 1; i >= 0; i-- {
+		if v := o.PropagationPolicies[i]; v.filter(obj) {
+			options.PropagationPolicy = &v.policy
+			break
+		}
+	}
+
+	if current != nil && o.checksOwnership() {
+		uid, rv := current.GetUID(), current.GetResourceVersion()
+		options.Preconditions = &metav1.Preconditions{UID: &uid, ResourceVersion: &rv}
+	}
+
+	if o.PreconditionUID {
+		uid := obj.GetUID()
+		if len(uid) == 0 && current != nil {
+			uid = current.GetUID()
+		}
+
+		if len(uid) == 0 {
+			return options, fmt.Errorf("precondition on uid requested but %q has no metadata.uid", obj.GetName())
+		}
+
+		if options.Preconditions == nil {
+			options.Preconditions = &metav1.Preconditions{}
+		}
+
+		options.Preconditions.UID = &uid
+	}
+
+	if o.PreconditionResourceVersion {
+		rv := obj.GetResourceVersion()
+		if len(rv) == 0 && current != nil {
+			rv = current.GetResourceVersion()
+		}
+
+		if len(rv) == 0 {
+			return options, fmt.Errorf("precondition on resourceVersion requested but %q has no metadata.resourceVersion", obj.GetName())
+		}
+
+		if options.Preconditions == nil {
+			options.Preconditions = &metav1.Preconditions{}
+		}
+
+		options.Preconditions.ResourceVersion = &rv
+	}
+
+	return options, nil
+}
+
 func WaitForDelete() DeleteOptionFunc {
 	return func(c *deleteOptions) {
 		c.Wait = true
 	}
 }
+
+// WaitForDeleteBackoff waits for deletion polling with backoff. A backoff without Steps would give up before polling
+// once, so it gets as many steps as the default backoff.
+func WaitForDeleteBackoff(backoff wait.Backoff) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		if backoff.Steps <= 0 {
+			backoff.Steps = defaultBackoff.Steps
+		}
+
+		c.Wait = true
+		c.Backoff = backoff
+	}
+}
+
+// WaitForDeleteTimeout waits for deletion and bounds the whole Delete or DeleteCollection call, the delete requests
+// and the waits of every tier included, to timeout.
+func WaitForDeleteTimeout(timeout time.Duration) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.Wait = true
+		c.Timeout = timeout
+	}
+}
+
+// DetectStuckFinalizers stops waiting for objects being deleted whose finalizers have not changed for the given time,
+// reporting them instead.
+func DetectStuckFinalizers(timeout time.Duration) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.Wait = true
+		c.StuckFinalizerTimeout = timeout
+	}
+}
+
+// RemoveStuckFinalizers removes the finalizers of the objects detected as stuck, letting their deletion complete.
+// Whatever the finalizers were meant to clean up is left behind.
+func RemoveStuckFinalizers(timeout time.Duration) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.Wait = true
+		c.StuckFinalizerTimeout = timeout
+		c.RemoveStuckFinalizers = true
+	}
+}
+
+func KindPriorityForDelete(gk schema.GroupKind, priority int) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.Priorities[gk] = priority
+	}
+}
+
+func DryRunForDelete() DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.DryRun = true
+	}
+}
+
+func ContinueOnErrorForDelete() DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.ContinueOnError = true
+	}
+}
+
+func PropagationPolicy(policy metav1.DeletionPropagation) DeleteOptionFunc {
+	return PropagationPolicyFor(func(*unstructured.Unstructured) bool { return true }, policy)
+}
+
+func PropagationPolicyFor(filter Filter, policy metav1.DeletionPropagation) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.PropagationPolicies = append(c.PropagationPolicies, propagationPolicy{filter: filter, policy: policy})
+	}
+}
+
+func GracePeriodSeconds(seconds int64) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.GracePeriodSeconds = &seconds
+	}
+}
+
+// PreconditionUID only deletes objects whose uid matches the metadata.uid of the manifest when it was read back from
+// the cluster, or else the uid of the live object read just before the delete, so a replaced object is not deleted.
+func PreconditionUID() DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.PreconditionUID = true
+	}
+}
+
+// PreconditionResourceVersion only deletes objects whose resourceVersion matches the metadata.resourceVersion of the
+// manifest when it was read back from the cluster, or else that of the live object read just before the delete, so a
+// changed object is not deleted.
+func PreconditionResourceVersion() DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.PreconditionResourceVersion = true
+	}
+}
+
+// OnlyOwned skips the objects whose managed fields do not list the field manager of the Reader.
+func OnlyOwned() DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.OwnedByFieldManager = true
+	}
+}
+
+// OnlyOwnedByApplySet skips the objects that are not members of the ApplySet. Combined with OnlyOwned, objects owned
+// either way are deleted.
+func OnlyOwnedByApplySet(name, namespace string) DeleteOptionFunc {
+	return func(c *deleteOptions) {
+		c.OwnedByApplySets.Insert(newApplySet(name, namespace).id())
+	}
+}
@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestToDeleteOptionsPreconditions(t *testing.T) {
+	object := func(uid, rv string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetName("a")
+		obj.SetUID(types.UID(uid))
+		obj.SetResourceVersion(rv)
+
+		return obj
+	}
+
+	tests := []struct {
+		name    string
+		opts    []DeleteOptionFunc
+		obj     *unstructured.Unstructured
+		current *unstructured.Unstructured
+		uid     string
+		rv      string
+		wantErr bool
+	}{
+		{
+			name: "none",
+			obj:  object("", ""),
+		},
+		{
+			name:    "live object of the ownership check",
+			opts:    []DeleteOptionFunc{OnlyOwned()},
+			obj:     object("", ""),
+			current: object("live", "2"),
+			uid:     "live",
+			rv:      "2",
+		},
+		{
+			name: "uid from the manifest",
+			opts: []DeleteOptionFunc{PreconditionUID()},
+			obj:  object("manifest", ""),
+			uid:  "manifest",
+		},
+		{
+			name:    "uid from the live object",
+			opts:    []DeleteOptionFunc{PreconditionUID()},
+			obj:     object("", ""),
+			current: object("live", "2"),
+			uid:     "live",
+		},
+		{
+			name:    "resourceVersion from the manifest over the live object",
+			opts:    []DeleteOptionFunc{PreconditionResourceVersion()},
+			obj:     object("", "1"),
+			current: object("live", "2"),
+			rv:      "1",
+		},
+		{
+			name:    "uid missing",
+			opts:    []DeleteOptionFunc{PreconditionUID()},
+			obj:     object("", ""),
+			wantErr: true,
+		},
+		{
+			name:    "resourceVersion missing",
+			opts:    []DeleteOptionFunc{PreconditionResourceVersion()},
+			obj:     object("", ""),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, err := newDeleteOptions(tt.opts...).toDeleteOptions(tt.obj, tt.current)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toDeleteOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			var uid, rv string
+			if p := options.Preconditions; p != nil {
+				if p.UID != nil {
+					uid = string(*p.UID)
+				}
+
+				if p.ResourceVersion != nil {
+					rv = *p.ResourceVersion
+				}
+			}
+
+			if uid != tt.uid || rv != tt.rv {
+				t.Errorf("toDeleteOptions() preconditions = %q/%q, want %q/%q", uid, rv, tt.uid, tt.rv)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package manifest
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type DeleteAction string
+
+const (
+	DeleteActionDeleted DeleteAction = "deleted"
+	DeleteActionSkipped DeleteAction = "skipped"
+	DeleteActionFailed  DeleteAction = "failed"
+)
+
+// DeleteResult tells what Delete did with an object. Reason explains why a skipped object was left in place, e.g. not
+// being owned as required by OnlyOwned or OnlyOwnedByApplySet.
+type DeleteResult struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Action           DeleteAction
+	Reason           string
+	DryRun           bool
+	Err              error
+}
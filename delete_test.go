@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestDeleteNotFound(t *testing.T) {
+	configMap := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("default")
+		obj.SetName(name)
+
+		return obj
+	}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	l := &list{
+		resources: []*unstructured.Unstructured{configMap("present"), configMap("missing")},
+		client:    dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), configMap("present")),
+		mapper:    mapper,
+	}
+
+	results, err := l.Delete(context.Background())
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	want := map[string]DeleteResult{
+		"present": {Action: DeleteActionDeleted},
+		"missing": {Action: DeleteActionSkipped, Reason: "not found"},
+	}
+
+	if len(results) != len(want) {
+		t.Fatalf("Delete() returned %d results, want %d", len(results), len(want))
+	}
+
+	for _, v := range results {
+		if w := want[v.Name]; v.Action != w.Action || v.Reason != w.Reason {
+			t.Errorf("Delete() result for %q = %s %q, want %s %q", v.Name, v.Action, v.Reason, w.Action, w.Reason)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+type DiffResult struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Diff             string
+}
+
+func (l *list) Diff(ctx context.Context) ([]DiffResult, error) {
+	options := newApplyOptions(DryRunForApply())
+	results := make([]DiffResult, 0, l.Size())
+
+	for _, tier := range tiers(l.Resources(), options.Priorities) {
+		for _, v := range tier {
+			result, err := l.diff(ctx, v, options)
+			if err != nil {
+				return results, err
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func (l *list) diff(ctx context.Context, obj *unstructured.Unstructured, options *applyOptions) (DiffResult, error) {
+	gvk := obj.GroupVersionKind()
+	kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+	if len(gvk.Group) == 0 {
+		kind = strings.ToLower(gvk.Kind)
+	}
+
+	result := DiffResult{GroupVersionKind: gvk, Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+	current, err := l.find(ctx, obj)
+	if err != nil {
+		return result, err
+	}
+
+	merged, _, err := l.patch(ctx, obj, options)
+	if err != nil {
+		return result, err
+	}
+
+	live, err := toDiffYAML(current)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode YAML for %s %q: %w", kind, obj.GetName(), err)
+	}
+
+	desired, err := toDiffYAML(merged)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode YAML for %s %q: %w", kind, obj.GetName(), err)
+	}
+
+	name := strings.Join([]string{kind, obj.GetNamespace(), obj.GetName()}, ".")
+	if len(obj.GetNamespace()) == 0 {
+		name = strings.Join([]string{kind, obj.GetName()}, ".")
+	}
+
+	result.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(live),
+		B:        difflib.SplitLines(desired),
+		FromFile: "live/" + name,
+		ToFile:   "merged/" + name,
+		Context:  3,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to diff %s %q: %w", kind, obj.GetName(), err)
+	}
+
+	return result, nil
+}
+
+// toDiffYAML encodes the object as YAML without the fields that change on every write.
+func toDiffYAML(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,162 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// DocumentError is returned in strict mode for every document that cannot be read.
+type DocumentError struct {
+	Source   string
+	Document int
+	Line     int
+	Err      error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("%s: document %d (line %d): %v", e.Source, e.Document, e.Line, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// document is a single YAML or JSON document of a multi-document stream.
+type document struct {
+	data []byte
+	line int
+}
+
+// splitDocuments splits the data on "---" separators, the same way the YAML reader of apimachinery does, keeping the
+// line each document starts at. Documents without content are dropped.
+func splitDocuments(data []byte) []document {
+	var (
+		docs    []document
+		current bytes.Buffer
+		start   int
+	)
+
+	flush := func() {
+		if start > 0 {
+			docs = append(docs, document{data: bytes.Clone(current.Bytes()), line: start})
+		}
+
+		current.Reset()
+		start = 0
+	}
+
+	for i, line := range bytes.SplitAfter(data, []byte("\n")) {
+		trimmed := bytes.TrimSpace(line)
+
+		if rest, ok := bytes.CutPrefix(line, []byte("---")); ok {
+			if rest = bytes.TrimSpace(rest); len(rest) == 0 || rest[0] == '#' {
+				flush()
+				continue
+			}
+		}
+
+		if start == 0 && len(trimmed) > 0 && trimmed[0] != '#' {
+			start = i + 1
+		}
+
+		current.Write(line)
+	}
+
+	flush()
+
+	return docs
+}
+
+// decode returns nil for documents that are null.
+func (d document) decode() (*unstructured.Unstructured, error) {
+	data, err := yaml.YAMLToJSON(d.data)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, nil
+	}
+
+	out := &unstructured.Unstructured{}
+	if err = out.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// flatten expands List wrappers, such as the v1/List written by kubectl get -o yaml or typed lists like ConfigMapList,
+// into their items. Items of typed lists missing apiVersion or kind inherit them from the list.
+func flatten(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	if !strings.HasSuffix(obj.GetKind(), "List") || !obj.IsList() {
+		return []*unstructured.Unstructured{obj}, nil
+	}
+
+	list, err := obj.ToList()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*unstructured.Unstructured
+
+	for i := range list.Items {
+		item := &list.Items[i]
+
+		if kind := strings.TrimSuffix(obj.GetKind(), "List"); len(kind) > 0 {
+			if len(item.GetAPIVersion()) == 0 {
+				item.SetAPIVersion(obj.GetAPIVersion())
+			}
+
+			if len(item.GetKind()) == 0 {
+				item.SetKind(kind)
+			}
+		}
+
+		items, err := flatten(item)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, items...)
+	}
+
+	return resources, nil
+}
+
+// duplicateError reports an object read from origin that was already read from previous. Objects are the same when
+// they share group, kind, namespace and name, whatever their version.
+func duplicateError(origin, previous Origin) error {
+	err := fmt.Errorf("duplicate of document %d", previous.Document)
+	if previous.Source != origin.Source {
+		err = fmt.Errorf("duplicate of %s", previous)
+	}
+
+	return &DocumentError{Source: origin.Source, Document: origin.Document, Line: origin.Line, Err: err}
+}
+
+func validate(obj *unstructured.Unstructured) error {
+	var missing []string
+
+	if len(obj.GetAPIVersion()) == 0 {
+		missing = append(missing, "apiVersion")
+	}
+
+	if len(obj.GetKind()) == 0 {
+		missing = append(missing, "kind")
+	}
+
+	if len(obj.GetName()) == 0 {
+		missing = append(missing, "metadata.name")
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	type doc struct {
+		line int
+		name string
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want []doc
+	}{
+		{
+			name: "single document",
+			data: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			want: []doc{{line: 1, name: "a"}},
+		},
+		{
+			name: "separated documents",
+			data: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+			want: []doc{{line: 1, name: "a"}, {line: 6, name: "b"}},
+		},
+		{
+			name: "leading separator and comments",
+			data: "---\n# header\n\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			want: []doc{{line: 4, name: "a"}},
+		},
+		{
+			name: "separator with comment",
+			data: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n--- # next\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n",
+			want: []doc{{line: 1, name: "a"}, {line: 6, name: "b"}},
+		},
+		{
+			name: "empty and comment-only documents",
+			data: "---\n---\n# nothing here\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n---\n",
+			want: []doc{{line: 5, name: "a"}},
+		},
+		{
+			name: "separator inside a block scalar is indented",
+			data: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  script: |\n    ---\n    echo\n",
+			want: []doc{{line: 1, name: "a"}},
+		},
+		{
+			name: "CRLF line endings",
+			data: "apiVersion: v1\r\nkind: ConfigMap\r\nmetadata:\r\n  name: a\r\n---\r\napiVersion: v1\r\nkind: ConfigMap\r\nmetadata:\r\n  name: b\r\n",
+			want: []doc{{line: 1, name: "a"}, {line: 6, name: "b"}},
+		},
+		{
+			name: "document start marker with content is not a separator",
+			data: "--- {apiVersion: v1, kind: ConfigMap, metadata: {name: a}}\n",
+			want: []doc{{line: 1, name: "a"}},
+		},
+		{
+			name: "no trailing newline",
+			data: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a",
+			want: []doc{{line: 1, name: "a"}},
+		},
+		{
+			name: "empty input",
+			data: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := splitDocuments([]byte(tt.data))
+			if len(docs) != len(tt.want) {
+				t.Fatalf("splitDocuments() returned %d documents, want %d", len(docs), len(tt.want))
+			}
+
+			for i, d := range docs {
+				if d.line != tt.want[i].line {
+					t.Errorf("document %d line = %d, want %d", i, d.line, tt.want[i].line)
+				}
+
+				obj, err := d.decode()
+				if err != nil {
+					t.Fatalf("document %d decode() error = %v", i, err)
+				}
+
+				if obj == nil || obj.GetName() != tt.want[i].name {
+					t.Errorf("document %d decode() = %v, want name %q", i, obj, tt.want[i].name)
+				}
+			}
+		})
+	}
+}
+
+func TestDocumentDecodeNull(t *testing.T) {
+	obj, err := document{data: []byte("# only a comment\n")}.decode()
+	if err != nil || obj != nil {
+		t.Errorf("decode() = %v, %v, want nil, nil", obj, err)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "plain object",
+			data: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			want: []string{"v1/ConfigMap/a"},
+		},
+		{
+			name: "v1 List",
+			data: "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: ConfigMap\n  metadata:\n    name: a\n- apiVersion: apps/v1\n  kind: Deployment\n  metadata:\n    name: b\n",
+			want: []string{"v1/ConfigMap/a", "apps/v1/Deployment/b"},
+		},
+		{
+			name: "typed list items inherit apiVersion and kind",
+			data: "apiVersion: apps/v1\nkind: DeploymentList\nitems:\n- metadata:\n    name: a\n",
+			want: []string{"apps/v1/Deployment/a"},
+		},
+		{
+			name: "nested lists",
+			data: "apiVersion: v1\nkind: List\nitems:\n- apiVersion: v1\n  kind: ConfigMapList\n  items:\n  - metadata:\n      name: a\n",
+			want: []string{"v1/ConfigMap/a"},
+		},
+		{
+			name: "empty list",
+			data: "apiVersion: v1\nkind: List\nitems: []\n",
+		},
+		{
+			name: "kind ending in List without items",
+			data: "apiVersion: example.io/v1\nkind: AllowList\nmetadata:\n  name: a\n",
+			want: []string{"example.io/v1/AllowList/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj, err := document{data: []byte(tt.data)}.decode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			items, err := flatten(obj)
+			if err != nil {
+				t.Fatalf("flatten() error = %v", err)
+			}
+
+			var got []string
+			for _, v := range items {
+				got = append(got, v.GetAPIVersion()+"/"+v.GetKind()+"/"+v.GetName())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("flatten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenInvalidItems(t *testing.T) {
+	obj, err := document{data: []byte("apiVersion: v1\nkind: List\nitems:\n- not an object\n")}.decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := flatten(obj); err == nil {
+		t.Error("flatten() error = nil, want an error for items that are not objects")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -201,6 +202,23 @@ func (m *dynamicRESTMapper) addKnownGroupAndReload(groupName string, versions ..
 	}
 
 	m.knownGroups[groupName] = groupResources
+	m.reload()
+
+	return nil
+}
+
+// invalidateGroup forgets everything known about the group, forcing it to be discovered again on the next lookup.
+func (m *dynamicRESTMapper) invalidateGroup(groupName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.knownGroups, groupName)
+	delete(m.apiGroups, groupName)
+	m.reload()
+}
+
+// reload rebuilds the underlying mapper from the known groups. The caller must hold the write lock.
+func (m *dynamicRESTMapper) reload() {
 	updatedGroupResources := make([]*restmapper.APIGroupResources, 0, len(m.knownGroups))
 
 	for _, agr := range m.knownGroups {
@@ -208,8 +226,6 @@ func (m *dynamicRESTMapper) addKnownGroupAndReload(groupName string, versions ..
 	}
 
 	m.mapper = restmapper.NewDiscoveryRESTMapper(updatedGroupResources)
-
-	return nil
 }
 
 func (m *dynamicRESTMapper) findAPIGroupByName(groupName string) (*metav1.APIGroup, error) {
@@ -248,7 +264,8 @@ func (m *dynamicRESTMapper) findAPIGroupByName(groupName string) (*metav1.APIGro
 		}
 	}
 
-	return nil, nil
+	// the group is not served, e.g. its CRD was uninstalled
+	return nil, &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: groupName}}
 }
 
 func (m *dynamicRESTMapper) fetchGroupVersionResources(groupName string, versions ...string) (map[schema.GroupVersion]*metav1.APIResourceList, error) {
@@ -259,6 +276,11 @@ func (m *dynamicRESTMapper) fetchGroupVersionResources(groupName string, version
 		groupVersion := schema.GroupVersion{Group: groupName, Version: version}
 
 		apiResourceList, err := m.client.ServerResourcesForGroupVersion(groupVersion.String())
+		if apierrors.IsNotFound(err) {
+			// not served, the mapper will report it as no match
+			continue
+		}
+
 		if err != nil {
 			failedGroups[groupVersion] = err
 		}
@@ -1,8 +1,6 @@
 package manifest
 
 import (
-	"fmt"
-
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -21,7 +19,7 @@ func (l *list) Filter(funcs ...Filter) List {
 		}
 	}
 
-	return &list{resources: resources, fieldManager: l.fieldManager, client: l.client, mapper: l.mapper}
+	return &list{resources: resources, origins: l.origins, fieldManager: l.fieldManager, client: l.client, mapper: l.mapper}
 }
 
 func All(filters ...Filter) Filter {
@@ -101,16 +99,13 @@ func ByLabels(labels map[string]string) Filter {
 }
 
 func In(manifest List) Filter {
-	key := func(u *unstructured.Unstructured) string {
-		return fmt.Sprintf("%s|%s/%s", u.GroupVersionKind().GroupKind(), u.GetNamespace(), u.GetName())
-	}
 	index := sets.NewString()
 
 	for _, u := range manifest.Resources() {
-		index.Insert(key(u))
+		index.Insert(resourceKey(u))
 	}
 
 	return func(u *unstructured.Unstructured) bool {
-		return index.Has(key(u))
+		return index.Has(resourceKey(u))
 	}
 }
@@ -0,0 +1,111 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// finalizersSnapshot records since when an object being deleted has had the same finalizers.
+type finalizersSnapshot struct {
+	finalizers []string
+	since      time.Time
+}
+
+func (s finalizersSnapshot) equal(finalizers []string) bool {
+	if len(s.finalizers) != len(finalizers) {
+		return false
+	}
+
+	for i := range finalizers {
+		if s.finalizers[i] != finalizers[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+var namespaceGroupKind = schema.GroupKind{Kind: "Namespace"}
+
+// finalizersOf returns the finalizers holding back the deletion of obj. Namespaces are also held back by the
+// finalizers in their spec, such as kubernetes, which the namespace controller removes once the namespace is empty.
+func finalizersOf(obj *unstructured.Unstructured) []string {
+	finalizers := obj.GetFinalizers()
+
+	if obj.GroupVersionKind().GroupKind() == namespaceGroupKind {
+		spec, _, _ := unstructured.NestedStringSlice(obj.Object, "spec", "finalizers")
+		finalizers = append(append([]string{}, finalizers...), spec...)
+	}
+
+	return finalizers
+}
+
+// removeFinalizers clears the finalizers of the live object current. The spec finalizers of a namespace can only be
+// cleared through its finalize subresource.
+func (l *list) removeFinalizers(ctx context.Context, current *unstructured.Unstructured) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	gvk := current.GroupVersionKind()
+	kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+	if len(gvk.Group) == 0 {
+		kind = strings.ToLower(gvk.Kind)
+	}
+
+	mapper, err := l.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve REST mapping for %s: %w", kind, err)
+	}
+
+	resource := l.client.Resource(mapper.Resource).Namespace(current.GetNamespace())
+	if mapper.Scope.Name() == meta.RESTScopeNameRoot {
+		resource = l.client.Resource(mapper.Resource)
+	}
+
+	latest := current
+
+	if len(current.GetFinalizers()) > 0 {
+		data := []byte(`{"metadata":{"finalizers":null}}`)
+
+		latest, err = resource.Patch(ctx, current.GetName(), types.MergePatchType, data, metav1.PatchOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to remove finalizers from %s %q: %w", kind, current.GetName(), err)
+		}
+	}
+
+	if spec, _, _ := unstructured.NestedStringSlice(latest.Object, "spec", "finalizers"); gvk.GroupKind() == namespaceGroupKind && len(spec) > 0 {
+		// the update is checked against the resourceVersion, so it starts from the patched object
+		finalized := latest.DeepCopy()
+
+		if err = unstructured.SetNestedStringSlice(finalized.Object, []string{}, "spec", "finalizers"); err != nil {
+			return err
+		}
+
+		_, err = resource.Update(ctx, finalized, metav1.UpdateOptions{}, "finalize")
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to finalize %s %q: %w", kind, current.GetName(), err)
+		}
+	}
+
+	log.Info(fmt.Sprintf("%s %q finalizers removed", kind, current.GetName()))
+
+	return nil
+}
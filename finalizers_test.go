@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func terminatingNamespace() *unstructured.Unstructured {
+	ns := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "tenant-a"},
+		"spec":       map[string]interface{}{"finalizers": []interface{}{"kubernetes"}},
+	}}
+	ns.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+
+	return ns
+}
+
+func namespaceList(client *dynamicfake.FakeDynamicClient) *list {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	return &list{resources: []*unstructured.Unstructured{terminatingNamespace()}, client: client, mapper: mapper}
+}
+
+func TestFinalizersOf(t *testing.T) {
+	ns := terminatingNamespace()
+	ns.SetFinalizers([]string{"example.com/cleanup"})
+
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "a", "finalizers": []interface{}{"example.com/cleanup"}},
+		"spec":       map[string]interface{}{"finalizers": []interface{}{"ignored"}},
+	}}
+
+	if got := strings.Join(finalizersOf(ns), ","); got != "example.com/cleanup,kubernetes" {
+		t.Errorf("finalizersOf(namespace) = %s, want example.com/cleanup,kubernetes", got)
+	}
+
+	if got := strings.Join(finalizersOf(cm), ","); got != "example.com/cleanup" {
+		t.Errorf("finalizersOf(configmap) = %s, want example.com/cleanup", got)
+	}
+}
+
+func TestWaitForDeletionStuckNamespace(t *testing.T) {
+	backoff := wait.Backoff{Duration: 5 * time.Millisecond, Factor: 1, Steps: 50}
+
+	t.Run("detect", func(t *testing.T) {
+		l := namespaceList(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), terminatingNamespace()))
+
+		err := l.waitForDeletion(context.Background(), l.resources, newDeleteOptions(WaitForDeleteBackoff(backoff), DetectStuckFinalizers(time.Millisecond)))
+		if err == nil || !strings.Contains(err.Error(), `namespace "tenant-a" is stuck on finalizers: kubernetes`) {
+			t.Errorf("waitForDeletion() error = %v, want the namespace reported as stuck", err)
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), terminatingNamespace())
+		gvr := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+		var finalized []string
+
+		// the namespace controller deletes the namespace once nothing holds it back
+		client.PrependReactor("update", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			update := action.(k8stesting.UpdateAction)
+			if update.GetSubresource() != "finalize" {
+				return false, nil, nil
+			}
+
+			obj := update.GetObject().(*unstructured.Unstructured)
+			finalized, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "finalizers")
+
+			return true, obj, client.Tracker().Delete(gvr, "", obj.GetName())
+		})
+
+		l := namespaceList(client)
+
+		err := l.waitForDeletion(context.Background(), l.resources, newDeleteOptions(WaitForDeleteBackoff(backoff), RemoveStuckFinalizers(time.Millisecond)))
+		if err != nil {
+			t.Fatalf("waitForDeletion() error = %v", err)
+		}
+
+		if finalized == nil || len(finalized) > 0 {
+			t.Errorf("finalize subresource called with spec.finalizers = %v, want an empty list", finalized)
+		}
+	})
+}
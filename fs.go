@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// osFS exposes the operating system file system as an fs.FS. Unlike os.DirFS it is not rooted, so it accepts the same
+// relative and absolute paths os.Open does.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// resolve returns the absolute path name points to once every symlink is followed.
+func (osFS) resolve(name string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Abs(resolved)
+}
+
+// linkResolver is implemented by file systems able to follow symlinks. Only the OS file system is, since io/fs has no
+// way to read a link.
+type linkResolver interface {
+	resolve(name string) (string, error)
+}
+
+func resolveLink(fsys fs.FS, name string) (string, error) {
+	resolver, ok := fsys.(linkResolver)
+	if !ok {
+		return "", errors.New("symlinks cannot be resolved in this file system")
+	}
+
+	return resolver.resolve(name)
+}
@@ -0,0 +1,235 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func (l *list) waitForReady(ctx context.Context, resources []*unstructured.Unstructured) error {
+	log := logr.FromContextOrDiscard(ctx)
+	pending := resources
+	reasons := make(map[*unstructured.Unstructured]string, len(resources))
+
+	err := wait.ExponentialBackoffWithContext(ctx, defaultBackoff, func(ctx context.Context) (done bool, err error) {
+		remaining := make([]*unstructured.Unstructured, 0, len(pending))
+
+		for _, obj := range pending {
+			gvk := obj.GroupVersionKind()
+			kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+			if len(gvk.Group) == 0 {
+				kind = strings.ToLower(gvk.Kind)
+			}
+
+			current, err := l.find(ctx, obj)
+			if err != nil {
+				return false, err
+			}
+
+			if reason := failure(current); len(reason) > 0 {
+				err := l.newResourceError(obj, OperationWait, fmt.Errorf("%s %q failed: %s", kind, obj.GetName(), reason))
+				return false, fmt.Errorf("failed to wait for resources to become ready: %w", err)
+			}
+
+			ready, reason := false, "not found"
+			if current != nil {
+				ready, reason = isReady(current)
+			}
+
+			if ready {
+				log.Info(fmt.Sprintf("%s %q ready", kind, obj.GetName()))
+				continue
+			}
+
+			reasons[obj] = reason
+			remaining = append(remaining, obj)
+		}
+
+		pending = remaining
+
+		return len(pending) == 0, nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	if !wait.Interrupted(err) {
+		return err
+	}
+
+	errs := make([]error, 0, len(pending))
+
+	for _, obj := range pending {
+		gvk := obj.GroupVersionKind()
+		kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+		if len(gvk.Group) == 0 {
+			kind = strings.ToLower(gvk.Kind)
+		}
+
+		errs = append(errs, l.newResourceError(obj, OperationWait, fmt.Errorf("%s %q is not ready: %s", kind, obj.GetName(), reasons[obj])))
+	}
+
+	return fmt.Errorf("failed to wait for resources to become ready: %w", errors.Join(errs...))
+}
+
+// failure tells why the object will never become ready, such as a Job that ran out of retries, so waiting stops
+// early. It is empty while the object may still become ready.
+func failure(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return ""
+	}
+
+	if obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: "batch", Kind: "Job"}) {
+		if condition := findCondition(obj, "Failed"); condition != nil && condition["status"] == string(metav1.ConditionTrue) {
+			return conditionReason(condition)
+		}
+	}
+
+	return ""
+}
+
+// isReady reports whether the object reached its desired state and, if not, why.
+func isReady(obj *unstructured.Unstructured) (bool, string) {
+	generation := obj.GetGeneration()
+
+	observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if found && observedGeneration < generation {
+		return false, fmt.Sprintf("generation %d not observed yet", generation)
+	}
+
+	switch gk := obj.GroupVersionKind().GroupKind(); gk {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		return isDeploymentReady(obj)
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		return isStatefulSetReady(obj)
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}:
+		return isDaemonSetReady(obj)
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		return isJobReady(obj)
+	case schema.GroupKind{Group: "", Kind: "PersistentVolumeClaim"}:
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		return phase == "Bound", fmt.Sprintf("phase is %q", phase)
+	case crdGroupKind:
+		return isEstablished(obj), "not established"
+	}
+
+	if condition := findCondition(obj, "Ready"); condition != nil && condition["status"] != string(metav1.ConditionTrue) {
+		return false, conditionReason(condition)
+	}
+
+	return true, ""
+}
+
+func isDeploymentReady(obj *unstructured.Unstructured) (bool, string) {
+	if condition := findCondition(obj, "Progressing"); condition != nil && condition["reason"] == "ProgressDeadlineExceeded" {
+		return false, conditionReason(condition)
+	}
+
+	replicas := int64Field(obj, 1, "spec", "replicas")
+	updated := int64Field(obj, 0, "status", "updatedReplicas")
+	total := int64Field(obj, 0, "status", "replicas")
+	available := int64Field(obj, 0, "status", "availableReplicas")
+
+	switch {
+	case updated < replicas:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+	case total > updated:
+		return false, fmt.Sprintf("%d old replicas pending termination", total-updated)
+	case available < updated:
+		return false, fmt.Sprintf("%d of %d updated replicas available", available, updated)
+	}
+
+	return true, ""
+}
+
+func isStatefulSetReady(obj *unstructured.Unstructured) (bool, string) {
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return true, ""
+	}
+
+	replicas := int64Field(obj, 1, "spec", "replicas")
+	partition := int64Field(obj, 0, "spec", "updateStrategy", "rollingUpdate", "partition")
+	ready := int64Field(obj, 0, "status", "readyReplicas")
+	updated := int64Field(obj, 0, "status", "updatedReplicas")
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+
+	switch {
+	case ready < replicas:
+		return false, fmt.Sprintf("%d of %d replicas ready", ready, replicas)
+	case partition > 0 && updated < replicas-partition:
+		return false, fmt.Sprintf("%d of %d replicas updated", updated, replicas-partition)
+	case partition == 0 && currentRevision != updateRevision:
+		return false, fmt.Sprintf("revision %q not rolled out yet", updateRevision)
+	}
+
+	return true, ""
+}
+
+func isDaemonSetReady(obj *unstructured.Unstructured) (bool, string) {
+	desired := int64Field(obj, 0, "status", "desiredNumberScheduled")
+	updated := int64Field(obj, 0, "status", "updatedNumberScheduled")
+	available := int64Field(obj, 0, "status", "numberAvailable")
+
+	switch {
+	case updated < desired:
+		return false, fmt.Sprintf("%d of %d pods updated", updated, desired)
+	case available < desired:
+		return false, fmt.Sprintf("%d of %d pods available", available, desired)
+	}
+
+	return true, ""
+}
+
+func isJobReady(obj *unstructured.Unstructured) (bool, string) {
+	if condition := findCondition(obj, "Complete"); condition != nil && condition["status"] == string(metav1.ConditionTrue) {
+		return true, ""
+	}
+
+	return false, "not complete"
+}
+
+func findCondition(obj *unstructured.Unstructured, conditionType string) map[string]interface{} {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	for _, v := range conditions {
+		if condition, ok := v.(map[string]interface{}); ok && condition["type"] == conditionType {
+			return condition
+		}
+	}
+
+	return nil
+}
+
+func conditionReason(condition map[string]interface{}) string {
+	reason := fmt.Sprintf("%s is %v", condition["type"], condition["status"])
+
+	if v, ok := condition["reason"].(string); ok && len(v) > 0 {
+		reason = fmt.Sprintf("%s (%s)", reason, v)
+	}
+
+	if v, ok := condition["message"].(string); ok && len(v) > 0 {
+		reason = fmt.Sprintf("%s: %s", reason, v)
+	}
+
+	return reason
+}
+
+func int64Field(obj *unstructured.Unstructured, defaultValue int64, fields ...string) int64 {
+	v, found, err := unstructured.NestedInt64(obj.Object, fields...)
+	if !found || err != nil {
+		return defaultValue
+	}
+
+	return v
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func job(conditions ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]interface{}{"name": "migrate", "namespace": "default"},
+		"status":     map[string]interface{}{"conditions": conditions},
+	}}
+}
+
+func TestFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want string
+	}{
+		{
+			name: "failed job",
+			obj:  job(map[string]interface{}{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"}),
+			want: "Failed is True (BackoffLimitExceeded)",
+		},
+		{
+			name: "running job",
+			obj:  job(),
+		},
+		{
+			name: "complete job",
+			obj:  job(map[string]interface{}{"type": "Complete", "status": "True"}),
+		},
+		{
+			name: "missing object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failure(tt.obj); got != tt.want {
+				t.Errorf("failure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForReadyFailedJob(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, meta.RESTScopeNamespace)
+
+	failed := job(map[string]interface{}{"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded"})
+	l := &list{client: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), failed.DeepCopy()), mapper: mapper}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := l.waitForReady(ctx, []*unstructured.Unstructured{failed})
+
+	var resourceErr *ResourceError
+	if !errors.As(err, &resourceErr) || !strings.Contains(err.Error(), "BackoffLimitExceeded") {
+		t.Fatalf("waitForReady() error = %v, want the failed Job reported", err)
+	}
+
+	if ctx.Err() != nil {
+		t.Error("waitForReady() kept polling a failed Job until the deadline")
+	}
+}
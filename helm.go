@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// FromHelmChart renders a chart directory or archive the way helm template does, without a release being stored, and
+// reads the resulting resources along with the CRDs in the crds/ directories of the chart and its dependencies. Test
+// hooks are left out, since they are only meant to run on helm test.
+// Namespaced resources not setting a namespace are placed in namespace, as helm install would.
+func (r *Reader) FromHelmChart(chartPathOrArchive, releaseName, namespace string, values map[string]interface{}) (List, error) {
+	chart, err := loader.Load(chartPathOrArchive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %q: %w", chartPathOrArchive, err)
+	}
+
+	if err = chartutil.ProcessDependencies(chart, values); err != nil {
+		return nil, fmt.Errorf("failed to process dependencies of chart %q: %w", chartPathOrArchive, err)
+	}
+
+	options := chartutil.ReleaseOptions{Name: releaseName, Namespace: namespace, Revision: 1, IsInstall: true}
+
+	renderValues, err := chartutil.ToRenderValues(chart, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute values of chart %q: %w", chartPathOrArchive, err)
+	}
+
+	templates, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %q: %w", chartPathOrArchive, err)
+	}
+
+	files := make(map[string][]byte, len(templates))
+
+	for _, crd := range chart.CRDObjects() {
+		files[crd.Filename] = crd.File.Data
+	}
+
+	for name, content := range templates {
+		if path.Base(name) == "NOTES.txt" || len(strings.TrimSpace(content)) == 0 {
+			continue
+		}
+
+		files[name] = []byte(content)
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var resources []*unstructured.Unstructured
+
+	// origins are indexed once namespaces are set, since resourceKey depends on them
+	found := make(map[*unstructured.Unstructured]Origin)
+
+	for _, name := range names {
+		els, err := r.fromBytes(files[name], name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chart %q: %w", chartPathOrArchive, err)
+		}
+
+		for _, v := range els.Resources() {
+			if isTestHook(v) {
+				continue
+			}
+
+			resources = append(resources, v)
+			found[v], _ = els.Origin(v)
+		}
+	}
+
+	if err = r.defaultNamespace(resources, namespace); err != nil {
+		return nil, fmt.Errorf("failed to read chart %q: %w", chartPathOrArchive, err)
+	}
+
+	if r.strict {
+		seen := make(map[string]Origin, len(resources))
+
+		var errs []error
+
+		for _, v := range resources {
+			if previous, ok := seen[resourceKey(v)]; ok {
+				errs = append(errs, duplicateError(found[v], previous))
+
+				continue
+			}
+
+			seen[resourceKey(v)] = found[v]
+		}
+
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("failed to read chart %q: unable to parse manifest: %w", chartPathOrArchive, errors.Join(errs...))
+		}
+	}
+
+	origins := make(map[string]Origin, len(resources))
+	for _, v := range resources {
+		origins[resourceKey(v)] = found[v]
+	}
+
+	return r.newList(resources, origins), nil
+}
+
+// defaultNamespace sets namespace on namespaced resources without one. Kinds defined by the CRDs being read take their
+// scope from them, others are looked up in the cluster and left untouched when unknown there too.
+func (r *Reader) defaultNamespace(resources []*unstructured.Unstructured, namespace string) error {
+	scopes := make(map[schema.GroupKind]string)
+
+	for _, v := range resources {
+		if v.GroupVersionKind().GroupKind() != crdGroupKind {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(v.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(v.Object, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(v.Object, "spec", "scope")
+		scopes[schema.GroupKind{Group: group, Kind: kind}] = scope
+	}
+
+	for _, v := range resources {
+		if len(v.GetNamespace()) > 0 {
+			continue
+		}
+
+		gvk := v.GroupVersionKind()
+
+		// the chart's own CRDs are usually not installed yet
+		if scope, ok := scopes[gvk.GroupKind()]; ok {
+			if scope == "Namespaced" {
+				v.SetNamespace(namespace)
+			}
+
+			continue
+		}
+
+		mapper, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+		switch {
+		case meta.IsNoMatchError(err):
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to retrieve REST mapping for %s: %w", gvk.Kind, err)
+		case mapper.Scope.Name() == meta.RESTScopeNameNamespace:
+			v.SetNamespace(namespace)
+		}
+	}
+
+	return nil
+}
+
+// isTestHook tells whether obj is a helm test hook, such as the pods under templates/tests/.
+func isTestHook(obj *unstructured.Unstructured) bool {
+	for _, hook := range strings.Split(obj.GetAnnotations()["helm.sh/hook"], ",") {
+		if strings.HasPrefix(strings.TrimSpace(hook), "test") {
+			return true
+		}
+	}
+
+	return false
+}
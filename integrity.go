@@ -0,0 +1,153 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+func verify(ctx context.Context, rawURL string, body []byte, options *urlOptions) error {
+	digest := sha256.Sum256(body)
+
+	// a pinned digest still holds when a checksum file is given, so whoever serves it cannot override the pin
+	var expected []string
+
+	if len(options.SHA256) > 0 {
+		expected = append(expected, options.SHA256)
+	}
+
+	if len(options.ChecksumURL) > 0 {
+		checksums, err := fetch(ctx, options.ChecksumURL, sameOriginHeader(rawURL, options.ChecksumURL, options.Header), options)
+		if err != nil {
+			return fmt.Errorf("failed to read checksum from URL %q: %w", options.ChecksumURL, err)
+		}
+
+		checksum, err := lookupChecksum(checksums, rawURL)
+		if err != nil {
+			return fmt.Errorf("failed to read checksum from URL %q: %w", options.ChecksumURL, err)
+		}
+
+		expected = append(expected, checksum)
+	}
+
+	for _, v := range expected {
+		if !strings.EqualFold(v, hex.EncodeToString(digest[:])) {
+			return fmt.Errorf("checksum mismatch: expected sha256 %s, got %s", v, hex.EncodeToString(digest[:]))
+		}
+	}
+
+	signature := options.Signature
+
+	if len(options.SignatureURL) > 0 {
+		var err error
+
+		if signature, err = fetch(ctx, options.SignatureURL, sameOriginHeader(rawURL, options.SignatureURL, options.Header), options); err != nil {
+			return fmt.Errorf("failed to read signature from URL %q: %w", options.SignatureURL, err)
+		}
+	}
+
+	if len(options.PublicKey) == 0 {
+		return nil
+	}
+
+	return verifySignature(options.PublicKey, decodeSignature(signature), body, digest[:])
+}
+
+// sameOriginHeader returns the headers set for the manifest URL when target is served by the same scheme and host, so
+// credentials are not leaked to a third party hosting checksums or signatures.
+func sameOriginHeader(rawURL, target string, header http.Header) http.Header {
+	from, err := url.Parse(rawURL)
+	if err != nil {
+		return http.Header{}
+	}
+
+	to, err := url.Parse(target)
+	if err != nil || !strings.EqualFold(from.Scheme, to.Scheme) || !strings.EqualFold(from.Host, to.Host) {
+		return http.Header{}
+	}
+
+	return header
+}
+
+// lookupChecksum finds the checksum of the file the URL points to. A file with a single checksum and no file names is
+// accepted as well.
+func lookupChecksum(checksums []byte, rawURL string) (string, error) {
+	name := path.Base(rawURL)
+	if u, err := url.Parse(rawURL); err == nil {
+		name = path.Base(u.Path)
+	}
+
+	var lines [][]string
+
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if len(lines) == 1 && len(lines[0]) == 1 {
+		return lines[0][0], nil
+	}
+
+	for _, fields := range lines {
+		if len(fields) == 2 && path.Base(strings.TrimPrefix(fields[1], "*")) == name {
+			return fields[0], nil
+		}
+	}
+
+	return "", fmt.Errorf("no checksum found for %q", name)
+}
+
+func decodeSignature(signature []byte) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return signature
+	}
+
+	return decoded
+}
+
+func verifySignature(publicKey, signature, body, digest []byte) error {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return errors.New("failed to decode PEM public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, body, signature) {
+			return errors.New("invalid ed25519 signature")
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, signature) {
+			return errors.New("invalid ECDSA signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLookupChecksum(t *testing.T) {
+	const (
+		a = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+		b = "60303ae22b998861bce3b28f33eec1be758a213c86c93c076dbe9f558c11c752"
+	)
+
+	tests := []struct {
+		name      string
+		checksums string
+		url       string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "sha256sum format",
+			checksums: a + "  install.yaml\n" + b + "  crds.yaml\n",
+			url:       "https://example.com/releases/v1/crds.yaml",
+			want:      b,
+		},
+		{
+			name:      "binary mode marker",
+			checksums: a + " *install.yaml\n",
+			url:       "https://example.com/install.yaml",
+			want:      a,
+		},
+		{
+			name:      "path in checksum file",
+			checksums: a + "  dist/install.yaml\n",
+			url:       "https://example.com/install.yaml",
+			want:      a,
+		},
+		{
+			name:      "query string is ignored",
+			checksums: a + "  install.yaml\n",
+			url:       "https://example.com/install.yaml?ref=main",
+			want:      a,
+		},
+		{
+			name:      "single bare checksum",
+			checksums: a + "\n",
+			url:       "https://example.com/install.yaml",
+			want:      a,
+		},
+		{
+			name:      "CRLF and blank lines",
+			checksums: "\r\n" + a + "  install.yaml\r\n\r\n",
+			url:       "https://example.com/install.yaml",
+			want:      a,
+		},
+		{
+			name:      "no matching file",
+			checksums: a + "  install.yaml\n",
+			url:       "https://example.com/other.yaml",
+			wantErr:   true,
+		},
+		{
+			name:      "empty file",
+			checksums: "",
+			url:       "https://example.com/install.yaml",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookupChecksum([]byte(tt.checksums), tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupChecksum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("lookupChecksum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameOriginHeader(t *testing.T) {
+	header := http.Header{"Authorization": []string{"Bearer token"}}
+
+	tests := []struct {
+		name   string
+		target string
+		want   bool
+	}{
+		{name: "same host", target: "https://example.com/install.yaml.sha256", want: true},
+		{name: "other host", target: "https://checksums.example.org/install.yaml.sha256"},
+		{name: "other scheme", target: "http://example.com/install.yaml.sha256"},
+		{name: "other port", target: "https://example.com:8443/install.yaml.sha256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sameOriginHeader("https://example.com/install.yaml", tt.target, header)
+			if sent := len(got.Get("Authorization")) > 0; sent != tt.want {
+				t.Errorf("sameOriginHeader() sent credentials = %v, want %v", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	body := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")
+	sum := sha256.Sum256(body)
+	digest := hex.EncodeToString(sum[:])
+	other := hex.EncodeToString(make([]byte, sha256.Size))
+
+	checksum := digest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(checksum + "  install.yaml\n"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		pinned   string
+		checksum string
+		wantErr  bool
+	}{
+		{name: "both match", pinned: digest, checksum: digest},
+		{name: "pinned digest does not match", pinned: other, checksum: digest, wantErr: true},
+		{name: "checksum file does not match", pinned: digest, checksum: other, wantErr: true},
+		{name: "checksum file only", checksum: digest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checksum = tt.checksum
+			options := newURLOptions(SHA256(tt.pinned), ChecksumURL(server.URL+"/install.yaml.sha256"))
+
+			err := verify(context.Background(), server.URL+"/install.yaml", body, options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
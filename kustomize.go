@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"fmt"
+	"io/fs"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// FromKustomization renders the kustomization in dir in-process, as kustomize build would, and reads the resulting
+// resources.
+func (r *Reader) FromKustomization(dir string) (List, error) {
+	return r.fromKustomization(filesys.MakeFsOnDisk(), dir)
+}
+
+// FromKustomizationFS renders the kustomization in dir within fsys. Bases referenced with relative paths must live in
+// fsys as well.
+func (r *Reader) FromKustomizationFS(fsys fs.FS, dir string) (List, error) {
+	memory := filesys.MakeFsInMemory()
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		return memory.WriteFile(name, data)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kustomization %q: %w", dir, err)
+	}
+
+	return r.fromKustomization(memory, dir)
+}
+
+func (r *Reader) fromKustomization(fsys filesys.FileSystem, dir string) (List, error) {
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization %q: %w", dir, err)
+	}
+
+	data, err := resources.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization %q: %w", dir, err)
+	}
+
+	return r.fromBytes(data, dir)
+}
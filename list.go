@@ -5,15 +5,19 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/dynamic"
 )
 
 type List interface {
-	Delete(ctx context.Context, opts ...DeleteOptionFunc) error
-	Apply(ctx context.Context) error
+	Delete(ctx context.Context, opts ...DeleteOptionFunc) ([]DeleteResult, error)
+	DeleteCollection(ctx context.Context, selector labels.Selector, opts ...DeleteOptionFunc) error
+	Apply(ctx context.Context, opts ...ApplyOptionFunc) ([]ApplyResult, error)
+	Diff(ctx context.Context) ([]DiffResult, error)
 	Filter(funcs ...Filter) List
 	Transform(funcs ...Transformer) (List, error)
 	Resources() []*unstructured.Unstructured
+	Origin(u *unstructured.Unstructured) (Origin, bool)
 	Size() int
 	Append(mfs ...List) List
 }
@@ -24,14 +28,22 @@ func EmptyList() List {
 
 type empty struct{}
 
-func (e *empty) Delete(ctx context.Context, opts ...DeleteOptionFunc) error {
-	return nil
+func (e *empty) Delete(ctx context.Context, opts ...DeleteOptionFunc) ([]DeleteResult, error) {
+	return nil, nil
 }
 
-func (e *empty) Apply(ctx context.Context) error {
+func (e *empty) DeleteCollection(ctx context.Context, selector labels.Selector, opts ...DeleteOptionFunc) error {
 	return nil
 }
 
+func (e *empty) Apply(ctx context.Context, opts ...ApplyOptionFunc) ([]ApplyResult, error) {
+	return nil, nil
+}
+
+func (e *empty) Diff(ctx context.Context) ([]DiffResult, error) {
+	return nil, nil
+}
+
 func (e *empty) Filter(funcs ...Filter) List {
 	return e
 }
@@ -44,17 +56,26 @@ func (e *empty) Resources() []*unstructured.Unstructured {
 	return nil
 }
 
+func (e *empty) Origin(u *unstructured.Unstructured) (Origin, bool) {
+	return Origin{}, false
+}
+
 func (e *empty) Size() int {
 	return 0
 }
 
 func (e *empty) Append(mfs ...List) List {
 	resources := make([]*unstructured.Unstructured, 0)
+	origins := make(map[string]Origin)
 
 	for _, mf := range mfs {
 		for _, v := range mf.Resources() {
 			resource := v.DeepCopy()
 			resources = append(resources, resource)
+
+			if origin, ok := mf.Origin(v); ok {
+				origins[resourceKey(resource)] = origin
+			}
 		}
 	}
 
@@ -74,11 +95,12 @@ func (e *empty) Append(mfs ...List) List {
 		}
 	}
 
-	return &list{resources: resources, fieldManager: fieldManager, client: client, mapper: mapper}
+	return &list{resources: resources, origins: origins, fieldManager: fieldManager, client: client, mapper: mapper}
 }
 
 type list struct {
 	resources    []*unstructured.Unstructured
+	origins      map[string]Origin
 	fieldManager string
 	client       dynamic.Interface
 	mapper       meta.RESTMapper
@@ -94,18 +116,27 @@ func (l *list) Size() int {
 
 func (l *list) Append(mfs ...List) List {
 	resources := make([]*unstructured.Unstructured, 0, l.Size())
+	origins := make(map[string]Origin, len(l.origins))
 
 	for _, v := range l.Resources() {
 		resource := v.DeepCopy()
 		resources = append(resources, resource)
 	}
 
+	for k, v := range l.origins {
+		origins[k] = v
+	}
+
 	for _, mf := range mfs {
 		for _, v := range mf.Resources() {
 			resource := v.DeepCopy()
 			resources = append(resources, resource)
+
+			if origin, ok := mf.Origin(v); ok {
+				origins[resourceKey(resource)] = origin
+			}
 		}
 	}
 
-	return &list{resources: resources, fieldManager: l.fieldManager, client: l.client, mapper: l.mapper}
+	return &list{resources: resources, origins: origins, fieldManager: l.fieldManager, client: l.client, mapper: l.mapper}
 }
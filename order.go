@@ -0,0 +1,123 @@
+package manifest
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Priorities used to order resources on Apply. Resources with a lower priority are applied first and deleted last.
+const (
+	PriorityNamespace = (iota + 1) * 100
+	PriorityCustomResourceDefinition
+	PriorityClusterConfig
+	PriorityRBAC
+	PriorityConfig
+	PriorityService
+	PriorityWorkload
+	PriorityDefault
+	PriorityWebhook
+)
+
+var defaultPriorities = map[schema.GroupKind]int{
+	{Group: "", Kind: "Namespace"}: PriorityNamespace,
+
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: PriorityCustomResourceDefinition,
+
+	{Group: "", Kind: "LimitRange"}:                     PriorityClusterConfig,
+	{Group: "", Kind: "PersistentVolume"}:               PriorityClusterConfig,
+	{Group: "", Kind: "ResourceQuota"}:                  PriorityClusterConfig,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:  PriorityClusterConfig,
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}: PriorityClusterConfig,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:        PriorityClusterConfig,
+	{Group: "policy", Kind: "PodDisruptionBudget"}:      PriorityClusterConfig,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}: PriorityClusterConfig,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:        PriorityClusterConfig,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:     PriorityClusterConfig,
+
+	{Group: "", Kind: "ServiceAccount"}:                              PriorityRBAC,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:        PriorityRBAC,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: PriorityRBAC,
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}:               PriorityRBAC,
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:        PriorityRBAC,
+
+	{Group: "", Kind: "ConfigMap"}:             PriorityConfig,
+	{Group: "", Kind: "PersistentVolumeClaim"}: PriorityConfig,
+	{Group: "", Kind: "Secret"}:                PriorityConfig,
+
+	{Group: "", Kind: "Endpoints"}:                     PriorityService,
+	{Group: "", Kind: "Service"}:                       PriorityService,
+	{Group: "discovery.k8s.io", Kind: "EndpointSlice"}: PriorityService,
+
+	{Group: "", Kind: "Pod"}:                                PriorityWorkload,
+	{Group: "", Kind: "ReplicationController"}:              PriorityWorkload,
+	{Group: "apps", Kind: "DaemonSet"}:                      PriorityWorkload,
+	{Group: "apps", Kind: "Deployment"}:                     PriorityWorkload,
+	{Group: "apps", Kind: "ReplicaSet"}:                     PriorityWorkload,
+	{Group: "apps", Kind: "StatefulSet"}:                    PriorityWorkload,
+	{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}: PriorityWorkload,
+	{Group: "batch", Kind: "CronJob"}:                       PriorityWorkload,
+	{Group: "batch", Kind: "Job"}:                           PriorityWorkload,
+
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             PriorityWebhook,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     PriorityWebhook,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        PriorityWebhook,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: PriorityWebhook,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   PriorityWebhook,
+}
+
+func newPriorities() map[schema.GroupKind]int {
+	priorities := make(map[schema.GroupKind]int, len(defaultPriorities))
+	for k, v := range defaultPriorities {
+		priorities[k] = v
+	}
+
+	return priorities
+}
+
+// tiers groups the resources by priority, in ascending order. The original order is kept within each tier.
+func tiers(resources []*unstructured.Unstructured, priorities map[schema.GroupKind]int) [][]*unstructured.Unstructured {
+	priorityOf := func(u *unstructured.Unstructured) int {
+		if v, ok := priorities[u.GroupVersionKind().GroupKind()]; ok {
+			return v
+		}
+
+		return PriorityDefault
+	}
+
+	sorted := make([]*unstructured.Unstructured, len(resources))
+	copy(sorted, resources)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return priorityOf(sorted[i]) < priorityOf(sorted[j])
+	})
+
+	var result [][]*unstructured.Unstructured
+
+	for i, v := range sorted {
+		if i == 0 || priorityOf(sorted[i-1]) != priorityOf(v) {
+			result = append(result, nil)
+		}
+
+		result[len(result)-1] = append(result[len(result)-1], v)
+	}
+
+	return result
+}
+
+// reverseTiers returns the tiers, and the resources within each tier, in reverse order.
+func reverseTiers(tiers [][]*unstructured.Unstructured) [][]*unstructured.Unstructured {
+	result := make([][]*unstructured.Unstructured, 0, len(tiers))
+
+	for i := len(tiers) - 1; i >= 0; i-- {
+		tier := make([]*unstructured.Unstructured, 0, len(tiers[i]))
+		for j := len(tiers[i]) - 1; j >= 0; j-- {
+			tier = append(tier, tiers[i][j])
+		}
+
+		result = append(result, tier)
+	}
+
+	return result
+}
@@ -0,0 +1,111 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTiers(t *testing.T) {
+	// objects are written as apiVersion/Kind/name
+	object := func(s string) *unstructured.Unstructured {
+		i := strings.LastIndex(s, "/")
+		j := strings.LastIndex(s[:i], "/")
+
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(s[:j])
+		obj.SetKind(s[j+1 : i])
+		obj.SetName(s[i+1:])
+
+		return obj
+	}
+
+	names := func(tiers [][]*unstructured.Unstructured) [][]string {
+		var result [][]string
+
+		for _, tier := range tiers {
+			var names []string
+			for _, v := range tier {
+				names = append(names, v.GetName())
+			}
+
+			result = append(result, names)
+		}
+
+		return result
+	}
+
+	widget := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+
+	tests := []struct {
+		name    string
+		objects []string
+		opts    []ApplyOptionFunc
+		want    [][]string
+		reverse [][]string
+	}{
+		{
+			name: "default priorities",
+			objects: []string{
+				"apps/v1/Deployment/web",
+				"example.com/v1/Widget/widget",
+				"admissionregistration.k8s.io/v1/ValidatingWebhookConfiguration/webhook",
+				"v1/ConfigMap/config",
+				"apiextensions.k8s.io/v1/CustomResourceDefinition/crd",
+				"v1/Namespace/ns",
+			},
+			want:    [][]string{{"ns"}, {"crd"}, {"config"}, {"web"}, {"widget"}, {"webhook"}},
+			reverse: [][]string{{"webhook"}, {"widget"}, {"web"}, {"config"}, {"crd"}, {"ns"}},
+		},
+		{
+			name: "priority override",
+			objects: []string{
+				"example.com/v1/Widget/widget",
+				"v1/ConfigMap/config",
+				"v1/Namespace/ns",
+			},
+			opts:    []ApplyOptionFunc{KindPriorityForApply(widget, PriorityNamespace)},
+			want:    [][]string{{"widget", "ns"}, {"config"}},
+			reverse: [][]string{{"config"}, {"ns", "widget"}},
+		},
+		{
+			name: "stable order within a tier",
+			objects: []string{
+				"v1/Secret/b",
+				"apps/v1/Deployment/web",
+				"v1/ConfigMap/a",
+				"v1/PersistentVolumeClaim/c",
+			},
+			want:    [][]string{{"b", "a", "c"}, {"web"}},
+			reverse: [][]string{{"web"}, {"c", "a", "b"}},
+		},
+		{
+			name: "no objects",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := make([]*unstructured.Unstructured, 0, len(tt.objects))
+			for _, v := range tt.objects {
+				resources = append(resources, object(v))
+			}
+
+			got := tiers(resources, newApplyOptions(tt.opts...).Priorities)
+			if !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("tiers() = %v, want %v", names(got), tt.want)
+			}
+
+			if reversed := names(reverseTiers(got)); !reflect.DeepEqual(reversed, tt.reverse) {
+				t.Errorf("reverseTiers() = %v, want %v", reversed, tt.reverse)
+			}
+
+			if len(tt.objects) > 0 && resources[0].GetName() != object(tt.objects[0]).GetName() {
+				t.Error("tiers() reordered its input")
+			}
+		})
+	}
+}
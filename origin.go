@@ -0,0 +1,29 @@
+package manifest
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Origin tells where a resource was read from. Document is the zero-based index of the document within the source and
+// Line the line the document starts at.
+type Origin struct {
+	Source   string
+	Document int
+	Line     int
+}
+
+func (o Origin) String() string {
+	return fmt.Sprintf("%s:%d (document %d)", o.Source, o.Line, o.Document)
+}
+
+// resourceKey identifies a resource regardless of its version, surviving deep copies.
+func resourceKey(u *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s|%s/%s", u.GroupVersionKind().GroupKind(), u.GetNamespace(), u.GetName())
+}
+
+func (l *list) Origin(u *unstructured.Unstructured) (Origin, bool) {
+	origin, ok := l.origins[resourceKey(u)]
+	return origin, ok
+}
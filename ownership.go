@@ -0,0 +1,71 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// deletable reports whether the live object exists and is owned by this field manager or one of the ApplySets
+// configured in the options. Ownership is not checked unless it was asked for. When the object is not deletable, the
+// reason tells which check failed. The live object checked is returned, so the delete can be made conditional on it;
+// it is also read when preconditions are asked for, as manifests read from files carry no uid or resourceVersion.
+func (l *list) deletable(ctx context.Context, obj *unstructured.Unstructured, options *deleteOptions) (*unstructured.Unstructured, bool, string, error) {
+	if !options.checksOwnership() && !options.PreconditionUID && !options.PreconditionResourceVersion {
+		return nil, true, "", nil
+	}
+
+	log := logr.FromContextOrDiscard(ctx)
+
+	current, err := l.find(ctx, obj)
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	if current == nil {
+		return nil, false, "not found", nil
+	}
+
+	if !options.checksOwnership() {
+		return current, true, "", nil
+	}
+
+	if options.OwnedByFieldManager {
+		for _, v := range current.GetManagedFields() {
+			if v.Manager == l.fieldManager {
+				return current, true, "", nil
+			}
+		}
+	}
+
+	if id, ok := current.GetLabels()[ApplySetPartOfLabel]; ok && options.OwnedByApplySets.Has(id) {
+		return current, true, "", nil
+	}
+
+	var reasons []string
+
+	if options.OwnedByFieldManager {
+		reasons = append(reasons, fmt.Sprintf("not managed by %q", l.fieldManager))
+	}
+
+	if len(options.OwnedByApplySets) > 0 {
+		reasons = append(reasons, fmt.Sprintf("not a member of ApplySet %s", strings.Join(sets.List(options.OwnedByApplySets), ", ")))
+	}
+
+	reason := strings.Join(reasons, " nor ")
+
+	gvk := obj.GroupVersionKind()
+	kind := fmt.Sprintf("%s.%s", strings.ToLower(gvk.Kind), gvk.Group)
+
+	if len(gvk.Group) == 0 {
+		kind = strings.ToLower(gvk.Kind)
+	}
+
+	log.Info(fmt.Sprintf("%s %q skipped: %s", kind, obj.GetName(), reason))
+
+	return nil, false, reason, nil
+}
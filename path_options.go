@@ -0,0 +1,107 @@
+package manifest
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+var (
+	defaultIncludes = []string{"*.yaml", "*.yml", "*.json"}
+	defaultExcludes = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+)
+
+type pathOptions struct {
+	Includes []string
+	Excludes []string
+	Hidden   bool
+
+	SymlinksWithinRoot bool
+}
+
+type PathOptionFunc func(c *pathOptions)
+
+func newPathOptions(opts ...PathOptionFunc) *pathOptions {
+	options := &pathOptions{Includes: defaultIncludes, Excludes: defaultExcludes}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// validate reports malformed patterns upfront, since doublestar.Match only fails once it reaches the bad part of a
+// pattern.
+func (o *pathOptions) validate() error {
+	for _, pattern := range append(append([]string{}, o.Includes...), o.Excludes...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+
+	return nil
+}
+
+// skip tells whether an entry found while reading a directory should be ignored. Name is relative to the directory
+// given to FromPath or FromFS. Includes are only checked against files, so a directory is only skipped when it is
+// hidden or excluded.
+func (o *pathOptions) skip(name string, dir bool) bool {
+	if !o.Hidden && strings.HasPrefix(path.Base(name), ".") {
+		return true
+	}
+
+	if matchAny(o.Excludes, name) {
+		return true
+	}
+
+	return !dir && !matchAny(o.Includes, name)
+}
+
+// matchAny matches patterns containing a slash against the whole relative name and the others against its base name,
+// like .gitignore does.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		subject := name
+		if !strings.Contains(pattern, "/") {
+			subject = path.Base(name)
+		}
+
+		if ok, _ := doublestar.Match(pattern, subject); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IncludeFiles replaces the default "*.yaml", "*.yml" and "*.json" patterns selecting which files are read from a
+// directory. Doublestar patterns such as "overlays/**/prod/*.yaml" are supported.
+func IncludeFiles(patterns ...string) PathOptionFunc {
+	return func(c *pathOptions) {
+		c.Includes = patterns
+	}
+}
+
+// ExcludeFiles skips files and directories matching any of the patterns, in addition to kustomization files.
+func ExcludeFiles(patterns ...string) PathOptionFunc {
+	return func(c *pathOptions) {
+		c.Excludes = append(append([]string{}, c.Excludes...), patterns...)
+	}
+}
+
+// IncludeHidden reads files and directories whose name starts with a dot, which are skipped by default.
+func IncludeHidden() PathOptionFunc {
+	return func(c *pathOptions) {
+		c.Hidden = true
+	}
+}
+
+// SymlinksWithinRoot fails the read when a symlink points outside the directory given to FromPath. Links cannot be
+// resolved through io/fs, so FromFS refuses every symlink with this option.
+func SymlinksWithinRoot() PathOptionFunc {
+	return func(c *pathOptions) {
+		c.SymlinksWithinRoot = true
+	}
+}
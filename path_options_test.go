@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFromFSPathOptions(t *testing.T) {
+	// every file holds a ConfigMap named after its path
+	fsys := fstest.MapFS{}
+	for _, name := range []string{
+		"a.yaml",
+		"b.yml",
+		"c.json",
+		"notes.txt",
+		"kustomization.yaml",
+		".hidden.yaml",
+		".git/config.yaml",
+		"base/kustomization.yml",
+		"base/Kustomization",
+		"base/deployment.yaml",
+		"overlays/prod/a.yaml",
+		"overlays/eu/prod/b.yaml",
+		"overlays/eu/prod/patch.json",
+		"overlays/eu/dev/c.yaml",
+		"secrets/token.yaml",
+	} {
+		data := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+		if strings.HasSuffix(name, ".json") {
+			data = `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "` + name + `"}}`
+		}
+
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		opts      []PathOptionFunc
+		want      []string
+	}{
+		{
+			name: "defaults",
+			want: []string{"a.yaml", "b.yml", "c.json"},
+		},
+		{
+			name:      "defaults recursive",
+			recursive: true,
+			want: []string{
+				"a.yaml", "b.yml", "base/deployment.yaml", "c.json", "overlays/eu/dev/c.yaml", "overlays/eu/prod/b.yaml",
+				"overlays/eu/prod/patch.json", "overlays/prod/a.yaml", "secrets/token.yaml",
+			},
+		},
+		{
+			name:      "doublestar include",
+			recursive: true,
+			opts:      []PathOptionFunc{IncludeFiles("overlays/**/prod/*.yaml")},
+			want:      []string{"overlays/eu/prod/b.yaml", "overlays/prod/a.yaml"},
+		},
+		{
+			name:      "excluded directory and base name",
+			recursive: true,
+			opts:      []PathOptionFunc{ExcludeFiles("secrets", "*.json", "overlays/eu/**")},
+			want:      []string{"a.yaml", "b.yml", "base/deployment.yaml", "overlays/prod/a.yaml"},
+		},
+		{
+			name:      "hidden files and directories",
+			recursive: true,
+			opts:      []PathOptionFunc{IncludeFiles("*.yaml"), IncludeHidden(), ExcludeFiles("base", "overlays", "secrets")},
+			want:      []string{".git/config.yaml", ".hidden.yaml", "a.yaml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := (&Reader{}).FromFS(fsys, ".", tt.recursive, tt.opts...)
+			if err != nil {
+				t.Fatalf("FromFS() error = %v", err)
+			}
+
+			var got []string
+			for _, v := range l.Resources() {
+				got = append(got, v.GetName())
+			}
+
+			sort.Strings(got)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FromFS() read %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromFSInvalidPattern(t *testing.T) {
+	if _, err := (&Reader{}).FromFS(fstest.MapFS{}, ".", true, IncludeFiles("[")); err == nil {
+		t.Error("FromFS() error = nil, want the invalid pattern reported")
+	}
+}
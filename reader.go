@@ -1,17 +1,20 @@
 package manifest
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 )
@@ -20,18 +23,21 @@ type Reader struct {
 	fieldManager string
 	client       dynamic.Interface
 	mapper       meta.RESTMapper
+	strict       bool
 }
 
-func NewReader(fieldManager string, config *rest.Config) (*Reader, error) {
+func NewReader(fieldManager string, config *rest.Config, opts ...ReaderOptionFunc) (*Reader, error) {
 	httpClient, err := rest.HTTPClientFor(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
-	return NewReaderForConfigAndClient(fieldManager, config, httpClient)
+	return NewReaderForConfigAndClient(fieldManager, config, httpClient, opts...)
 }
 
-func NewReaderForConfigAndClient(fieldManager string, config *rest.Config, httpClient *http.Client) (*Reader, error) {
+func NewReaderForConfigAndClient(fieldManager string, config *rest.Config, httpClient *http.Client, opts ...ReaderOptionFunc) (*Reader, error) {
+	options := newReaderOptions(opts...)
+
 	m, err := newDynamicRESTMapper(config, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize the manifest reader: %w", err)
@@ -42,112 +48,296 @@ func NewReaderForConfigAndClient(fieldManager string, config *rest.Config, httpC
 		return nil, fmt.Errorf("failed to initialize the manifest reader: %w", err)
 	}
 
-	return &Reader{fieldManager: fieldManager, client: c, mapper: m}, nil
+	return &Reader{fieldManager: fieldManager, client: c, mapper: m, strict: options.Strict}, nil
 }
 
 func (r *Reader) FromUnstructured(resources []*unstructured.Unstructured) (List, error) {
-	return &list{resources: resources, fieldManager: r.fieldManager, client: r.client, mapper: r.mapper}, nil
+	return r.newList(resources, nil), nil
+}
+
+func (r *Reader) newList(resources []*unstructured.Unstructured, origins map[string]Origin) *list {
+	return &list{resources: resources, origins: origins, fieldManager: r.fieldManager, client: r.client, mapper: r.mapper}
 }
 
 func (r *Reader) FromBytes(data []byte) (List, error) {
-	reader := bytes.NewReader(data)
-	decoder := yaml.NewYAMLToJSONDecoder(reader)
+	return r.fromBytes(data, "bytes")
+}
+
+func (r *Reader) fromBytes(data []byte, source string) (List, error) {
+	var (
+		resources []*unstructured.Unstructured
+		errs      []error
+	)
 
-	var resources []*unstructured.Unstructured
+	origins := make(map[string]Origin)
 
-	var err error
+	for i, doc := range splitDocuments(data) {
+		out, err := doc.decode()
+		if err != nil {
+			if r.strict {
+				errs = append(errs, &DocumentError{Source: source, Document: i, Line: doc.line, Err: err})
+			}
 
-	for {
-		out := &unstructured.Unstructured{}
+			continue
+		}
 
-		err = decoder.Decode(out)
-		if errors.Is(err, io.EOF) {
-			break
+		if out == nil {
+			continue
 		}
 
-		if err != nil || len(out.Object) == 0 {
+		items, err := flatten(out)
+		if err != nil {
+			if r.strict {
+				errs = append(errs, &DocumentError{Source: source, Document: i, Line: doc.line, Err: err})
+			}
+
 			continue
 		}
 
-		resources = append(resources, out)
+		for _, item := range items {
+			if r.strict {
+				if err := validate(item); err != nil {
+					errs = append(errs, &DocumentError{Source: source, Document: i, Line: doc.line, Err: err})
+
+					continue
+				}
+			}
+
+			origin := Origin{Source: source, Document: i, Line: doc.line}
+
+			if previous, ok := origins[resourceKey(item)]; ok && r.strict {
+				errs = append(errs, duplicateError(origin, previous))
+
+				continue
+			}
+
+			resources = append(resources, item)
+			origins[resourceKey(item)] = origin
+		}
 	}
 
-	if !errors.Is(err, io.EOF) {
-		return &list{}, fmt.Errorf("unable to parse manifest from bytes: %w", err)
+	if len(errs) > 0 {
+		return &list{}, fmt.Errorf("unable to parse manifest: %w", errors.Join(errs...))
 	}
 
-	return r.FromUnstructured(resources)
+	return r.newList(resources, origins), nil
 }
 
 func (r *Reader) FromURL(url string) (List, error) {
-	resp, err := http.Get(url)
+	return r.FromURLWithContext(context.Background(), url)
+}
+
+func (r *Reader) FromURLWithContext(ctx context.Context, url string, opts ...URLOptionFunc) (List, error) {
+	options := newURLOptions(opts...)
+
+	body, err := fetch(ctx, url, options.Header, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read manifests from URL %q: %w", url, err)
 	}
 
+	if err = verify(ctx, url, body, options); err != nil {
+		return nil, fmt.Errorf("failed to verify manifests from URL %q: %w", url, err)
+	}
+
+	return r.fromBytes(body, url)
+}
+
+func fetch(ctx context.Context, url string, header http.Header, options *urlOptions) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = header.Clone()
+
+	resp, err := options.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
 	defer func() { _ = resp.Body.Close() }()
 
-	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, options.MaxBodySize+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read manifests from URL %q: %w", url, err)
+		return nil, err
+	}
+
+	if int64(len(body)) > options.MaxBodySize {
+		return nil, fmt.Errorf("body exceeds %d bytes", options.MaxBodySize)
+	}
+
+	return body, nil
+}
+
+// FromPath reads manifests from a file or a directory. Only files matching the include patterns are read from
+// directories, see IncludeFiles, ExcludeFiles and IncludeHidden.
+func (r *Reader) FromPath(pathname string, recursive bool, opts ...PathOptionFunc) (List, error) {
+	return r.fromFS(osFS{}, pathname, recursive, newPathOptions(opts...))
+}
+
+// FromFS reads manifests from root within fsys, which may be a file or a directory. Directories are read the same way
+// FromPath reads them, so embedded manifests can be loaded with a //go:embed file system.
+func (r *Reader) FromFS(fsys fs.FS, root string, recursive bool, opts ...PathOptionFunc) (List, error) {
+	if !fs.ValidPath(root) {
+		return nil, fmt.Errorf("failed to read manifests from path %q: %w", root, fs.ErrInvalid)
 	}
 
-	return r.FromBytes(body)
+	return r.fromFS(fsys, root, recursive, newPathOptions(opts...))
 }
 
-func (r *Reader) FromPath(pathname string, recursive bool) (List, error) {
-	info, err := os.Stat(pathname)
+func (r *Reader) fromFS(fsys fs.FS, pathname string, recursive bool, options *pathOptions) (List, error) {
+	if err := options.validate(); err != nil {
+		return nil, fmt.Errorf("failed to read manifests from path %q: %w", pathname, err)
+	}
+
+	info, err := fs.Stat(fsys, pathname)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read manifests from path %q: %w", pathname, err)
 	}
 
 	if info.IsDir() {
-		return r.readDir(pathname, recursive)
+		w := &walk{fsys: fsys, options: options, recursive: recursive, parents: []fs.FileInfo{info}}
+
+		if _, ok := fsys.(linkResolver); ok && options.SymlinksWithinRoot {
+			if w.root, err = resolveLink(fsys, pathname); err != nil {
+				return nil, fmt.Errorf("failed to read manifests from path %q: %w", pathname, err)
+			}
+		}
+
+		return r.readDir(w, path.Clean(pathname), "")
 	}
 
-	return r.readFile(pathname)
+	return r.readFile(fsys, pathname)
 }
 
-func (r *Reader) readFile(pathname string) (List, error) {
-	file, err := os.ReadFile(pathname)
+func (r *Reader) readFile(fsys fs.FS, pathname string) (List, error) {
+	file, err := fs.ReadFile(fsys, pathname)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read manifests from file %q: %w", pathname, err)
 	}
 
-	return r.FromBytes(file)
+	return r.fromBytes(file, pathname)
+}
+
+// walk holds the state of a directory traversal.
+type walk struct {
+	fsys      fs.FS
+	options   *pathOptions
+	recursive bool
+	// root is the resolved path symlinks must point within, when SymlinksWithinRoot is set.
+	root string
+	// parents are the directories being read, used to detect symlink cycles.
+	parents []fs.FileInfo
 }
 
-func (r *Reader) readDir(pathname string, recursive bool) (List, error) {
-	contents, err := os.ReadDir(pathname)
+// readDir reads the directory pathname depth first, rel being its path relative to the directory the read started from.
+// Entries are visited in byte-wise lexical order of their names, files and directories alike, so the resulting List is
+// the same on every machine.
+func (r *Reader) readDir(w *walk, pathname, rel string) (List, error) {
+	contents, err := fs.ReadDir(w.fsys, pathname)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read manifests from dir %q: %w", pathname, err)
 	}
 
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].Name() < contents[j].Name()
+	})
+
 	resources := make([]*unstructured.Unstructured, 0)
+	origins := make(map[string]Origin)
+
+	var errs []error
 
 	for _, f := range contents {
 		name := path.Join(pathname, f.Name())
 
-		info, err := os.Stat(name)
+		info, err := fs.Stat(w.fsys, name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read manifests from dir %q: %w", pathname, err)
 		}
 
+		relName := path.Join(rel, f.Name())
+		if w.options.skip(relName, info.IsDir()) {
+			continue
+		}
+
+		if f.Type()&fs.ModeSymlink != 0 {
+			if err := w.checkLink(name); err != nil {
+				return nil, fmt.Errorf("failed to read manifests from dir %q: %w", pathname, err)
+			}
+		}
+
 		var els List
 
 		switch {
-		case info.IsDir() && recursive:
-			els, err = r.readDir(name, recursive)
+		case info.IsDir() && w.recursive:
+			if w.visiting(info) {
+				return nil, fmt.Errorf("failed to read manifests from dir %q: symlink cycle at %q", pathname, name)
+			}
+
+			w.parents = append(w.parents, info)
+			els, err = r.readDir(w, name, relName)
+			w.parents = w.parents[:len(w.parents)-1]
 		case !info.IsDir():
-			els, err = r.readFile(name)
+			els, err = r.readFile(w.fsys, name)
+		default:
+			continue
 		}
 
 		if err != nil {
 			return nil, err
 		}
 
-		resources = append(resources, els.Resources()...)
+		for _, v := range els.Resources() {
+			origin, _ := els.Origin(v)
+
+			if previous, ok := origins[resourceKey(v)]; ok && r.strict {
+				errs = append(errs, duplicateError(origin, previous))
+
+				continue
+			}
+
+			resources = append(resources, v)
+			origins[resourceKey(v)] = origin
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("unable to parse manifest: %w", errors.Join(errs...))
+	}
+
+	return r.newList(resources, origins), nil
+}
+
+// visiting tells whether dir is one of the directories being read, meaning a symlink loops back to it.
+func (w *walk) visiting(dir fs.FileInfo) bool {
+	for _, parent := range w.parents {
+		if os.SameFile(parent, dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkLink refuses symlinks pointing outside the root when SymlinksWithinRoot is set.
+func (w *walk) checkLink(name string) error {
+	if !w.options.SymlinksWithinRoot {
+		return nil
+	}
+
+	target, err := resolveLink(w.fsys, name)
+	if err != nil {
+		return err
+	}
+
+	if rel, err := filepath.Rel(w.root, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("symlink %q points outside of the root", name)
 	}
 
-	return r.FromUnstructured(resources)
+	return nil
 }
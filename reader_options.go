@@ -0,0 +1,24 @@
+package manifest
+
+type readerOptions struct {
+	Strict bool
+}
+
+type ReaderOptionFunc func(c *readerOptions)
+
+func newReaderOptions(opts ...ReaderOptionFunc) *readerOptions {
+	options := &readerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// Strict makes the Reader fail on malformed documents, objects without apiVersion, kind or metadata.name, and
+// duplicated objects, instead of silently dropping them.
+func Strict() ReaderOptionFunc {
+	return func(c *readerOptions) {
+		c.Strict = true
+	}
+}
@@ -0,0 +1,170 @@
+package manifest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFromFSStrictDuplicates(t *testing.T) {
+	deployment := func(version string) string {
+		return "apiVersion: " + version + "\nkind: Deployment\nmetadata:\n  name: a\n"
+	}
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "same file",
+			fsys: fstest.MapFS{"a.yaml": {Data: []byte(deployment("apps/v1") + "---\n" + deployment("apps/v1"))}},
+			want: "a.yaml: document 1 (line 6): duplicate of document 0",
+		},
+		{
+			name: "different files",
+			fsys: fstest.MapFS{"a.yaml": {Data: []byte(deployment("apps/v1"))}, "b.yaml": {Data: []byte(deployment("apps/v1"))}},
+			want: "b.yaml: document 0 (line 1): duplicate of a.yaml:1 (document 0)",
+		},
+		{
+			name: "different versions",
+			fsys: fstest.MapFS{"a.yaml": {Data: []byte(deployment("apps/v1"))}, "sub/b.yaml": {Data: []byte(deployment("apps/v1beta1"))}},
+			want: "sub/b.yaml: document 0 (line 1): duplicate of a.yaml:1 (document 0)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&Reader{strict: true}).FromFS(tt.fsys, ".", true)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("FromFS() error = %v, want %q", err, tt.want)
+			}
+
+			l, err := (&Reader{}).FromFS(tt.fsys, ".", true)
+			if err != nil || l.Size() != 2 {
+				t.Errorf("FromFS() without Strict = %v, %v, want both objects", l, err)
+			}
+		})
+	}
+}
+
+func TestFromPathSymlinks(t *testing.T) {
+	write := func(t *testing.T, name, data string) {
+		t.Helper()
+
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(name, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	link := func(t *testing.T, target, name string) {
+		t.Helper()
+
+		if err := os.Symlink(target, name); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, root, outside string)
+		opts    []PathOptionFunc
+		want    int
+		wantErr string
+	}{
+		{
+			name:    "cycle",
+			setup:   func(t *testing.T, root, _ string) { link(t, "..", filepath.Join(root, "sub", "loop")) },
+			wantErr: "symlink cycle at",
+		},
+		{
+			name:  "link outside the root",
+			setup: func(t *testing.T, root, outside string) { link(t, outside, filepath.Join(root, "external")) },
+			want:  3,
+		},
+		{
+			name:    "link outside the root refused",
+			setup:   func(t *testing.T, root, outside string) { link(t, outside, filepath.Join(root, "external")) },
+			opts:    []PathOptionFunc{SymlinksWithinRoot()},
+			wantErr: "points outside of the root",
+		},
+		{
+			name: "file link outside the root refused",
+			setup: func(t *testing.T, root, outside string) {
+				link(t, filepath.Join(outside, "c.yaml"), filepath.Join(root, "c.yaml"))
+			},
+			opts:    []PathOptionFunc{SymlinksWithinRoot()},
+			wantErr: "points outside of the root",
+		},
+		{
+			name:  "link within the root",
+			setup: func(t *testing.T, root, _ string) { link(t, "sub", filepath.Join(root, "alias")) },
+			opts:  []PathOptionFunc{SymlinksWithinRoot()},
+			want:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, outside := filepath.Join(t.TempDir(), "root"), filepath.Join(t.TempDir(), "outside")
+
+			write(t, filepath.Join(root, "a.yaml"), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n")
+			write(t, filepath.Join(root, "sub", "b.yaml"), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n")
+			write(t, filepath.Join(outside, "c.yaml"), "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: c\n")
+
+			tt.setup(t, root, outside)
+
+			l, err := (&Reader{}).FromPath(root, true, tt.opts...)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("FromPath() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FromPath() error = %v", err)
+			}
+
+			if l.Size() != tt.want {
+				t.Errorf("FromPath() read %d objects, want %d", l.Size(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		size    int64
+		wantErr bool
+	}{
+		{name: "zero keeps the default", size: 0},
+		{name: "negative keeps the default", size: -1},
+		{name: "too small", size: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fetch(context.Background(), server.URL, nil, newURLOptions(MaxBodySize(tt.size)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
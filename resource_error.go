@@ -0,0 +1,66 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type Operation string
+
+const (
+	OperationApply  Operation = "apply"
+	OperationDelete Operation = "delete"
+	OperationPrune  Operation = "prune"
+	OperationWait   Operation = "wait"
+)
+
+// ResourceError is returned when an operation fails for a single resource. Status is set when the API server rejected
+// the request.
+type ResourceError struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Operation        Operation
+	Status           *metav1.Status
+	Origin           *Origin
+	Err              error
+}
+
+func (l *list) newResourceError(obj *unstructured.Unstructured, operation Operation, err error) *ResourceError {
+	e := &ResourceError{
+		GroupVersionKind: obj.GroupVersionKind(),
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		Operation:        operation,
+		Err:              err,
+	}
+
+	if origin, ok := l.Origin(obj); ok {
+		e.Origin = &origin
+	}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		s := status.Status()
+		e.Status = &s
+	}
+
+	return e
+}
+
+func (e *ResourceError) Error() string {
+	if e.Origin != nil {
+		return fmt.Sprintf("%v (from %s)", e.Err, e.Origin)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
@@ -6,6 +6,7 @@ type Transformer func(u *unstructured.Unstructured) error
 
 func (l *list) Transform(funcs ...Transformer) (List, error) {
 	resources := make([]*unstructured.Unstructured, 0, l.Size())
+	origins := make(map[string]Origin, len(l.origins))
 
 	for _, v := range l.Resources() {
 		resource := v.DeepCopy()
@@ -16,7 +17,12 @@ func (l *list) Transform(funcs ...Transformer) (List, error) {
 		}
 
 		resources = append(resources, resource)
+
+		// transformers may rename the resource
+		if origin, ok := l.origins[resourceKey(v)]; ok {
+			origins[resourceKey(resource)] = origin
+		}
 	}
 
-	return &list{resources: resources, fieldManager: l.fieldManager, client: l.client, mapper: l.mapper}, nil
+	return &list{resources: resources, origins: origins, fieldManager: l.fieldManager, client: l.client, mapper: l.mapper}, nil
 }
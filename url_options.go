@@ -0,0 +1,94 @@
+package manifest
+
+import "net/http"
+
+const defaultMaxBodySize = 32 << 20
+
+type urlOptions struct {
+	Client      *http.Client
+	Header      http.Header
+	MaxBodySize int64
+
+	SHA256       string
+	ChecksumURL  string
+	PublicKey    []byte
+	Signature    []byte
+	SignatureURL string
+}
+
+type URLOptionFunc func(c *urlOptions)
+
+func newURLOptions(opts ...URLOptionFunc) *urlOptions {
+	options := &urlOptions{Client: http.DefaultClient, Header: http.Header{}, MaxBodySize: defaultMaxBodySize}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// HTTPClient fetches the manifests with client instead of http.DefaultClient, which is kept when client is nil.
+func HTTPClient(client *http.Client) URLOptionFunc {
+	return func(c *urlOptions) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+
+		c.Client = client
+	}
+}
+
+func Header(key, value string) URLOptionFunc {
+	return func(c *urlOptions) {
+		c.Header.Add(key, value)
+	}
+}
+
+func BearerToken(token string) URLOptionFunc {
+	return func(c *urlOptions) {
+		c.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// MaxBodySize limits the size of the downloaded manifests, checksum and signature files to size bytes. A size of zero
+// or less keeps the default of 32 MiB.
+func MaxBodySize(size int64) URLOptionFunc {
+	return func(c *urlOptions) {
+		if size <= 0 {
+			size = defaultMaxBodySize
+		}
+
+		c.MaxBodySize = size
+	}
+}
+
+func SHA256(digest string) URLOptionFunc {
+	return func(c *urlOptions) {
+		c.SHA256 = digest
+	}
+}
+
+// ChecksumURL verifies the manifests against a checksum file in the format of sha256sum, looking up the line that
+// matches the file name of the manifests URL. Headers, such as BearerToken, are only sent along when the checksum file
+// is served by the same host as the manifests. Along with SHA256, the manifests must match both checksums.
+func ChecksumURL(url string) URLOptionFunc {
+	return func(c *urlOptions) {
+		c.ChecksumURL = url
+	}
+}
+
+// Signature verifies a detached signature of the manifests, either raw or base64 encoded, with a PEM encoded ed25519
+// or ECDSA public key. ECDSA signatures are checked against the SHA-256 digest of the manifests, like cosign does.
+func Signature(publicKey, signature []byte) URLOptionFunc {
+	return func(c *urlOptions) {
+		c.PublicKey = publicKey
+		c.Signature = signature
+	}
+}
+
+func SignatureURL(publicKey []byte, url string) URLOptionFunc {
+	return func(c *urlOptions) {
+		c.PublicKey = publicKey
+		c.SignatureURL = url
+	}
+}